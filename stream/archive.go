@@ -0,0 +1,153 @@
+package stream
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"errors"
+	"io"
+	"io/fs"
+)
+
+// ErrEntryExpired is returned by ArchiveEntry.Open for an entry of a tar archive
+// when the stream has already moved past that entry.
+var ErrEntryExpired = errors.New("stream: archive entry is no longer available")
+
+// ArchiveEntry is an element of the streams returned by TarEntries, ZipEntries and ZipFileEntries.
+type ArchiveEntry struct {
+	// Name is the path of the entry inside of the archive.
+	Name string
+	// Info describes the entry.
+	Info fs.FileInfo
+	// TarHeader is the header of the entry, if it comes from a tar archive.
+	TarHeader *tar.Header
+	// ZipHeader is the header of the entry, if it comes from a zip archive.
+	ZipHeader *zip.FileHeader
+
+	open func() (io.ReadCloser, error)
+}
+
+// Open opens the content of the entry.
+//
+// The content of a tar entry can only be read before the next element of the stream is requested,
+// because tar archives can only be read sequentially.
+// After that Open returns ErrEntryExpired and reads from a previously opened content fail.
+//
+// The returned readers are closed when the stream is closed, if they have not been closed before that.
+func (e ArchiveEntry) Open() (io.ReadCloser, error) {
+	return e.open()
+}
+
+// TarEntries returns a lazy stream of the entries of the tar archive read from r.
+//
+// If r is an io.Closer, it is closed by the close handler of the stream.
+//
+// NOTE: There is no such thing in the Java standard library.
+func TarEntries(r io.Reader) Stream[ArchiveEntry] {
+	tr := tar.NewReader(r)
+	var current int64
+
+	s := newIteratorStream(func() (ArchiveEntry, bool, error) {
+		hdr, err := tr.Next()
+		current++
+		if err == io.EOF {
+			return ArchiveEntry{}, false, nil
+		}
+		if err != nil {
+			return ArchiveEntry{}, false, err
+		}
+		entry := current
+		return ArchiveEntry{
+			Name:      hdr.Name,
+			Info:      hdr.FileInfo(),
+			TarHeader: hdr,
+			open: func() (io.ReadCloser, error) {
+				if entry != current {
+					return nil, ErrEntryExpired
+				}
+				return &tarEntryReader{tr: tr, entry: entry, current: &current}, nil
+			},
+		}, true, nil
+	})
+	if c, ok := r.(io.Closer); ok {
+		s.OnClose(func() { _ = c.Close() })
+	}
+	return s
+}
+
+// tarEntryReader reads the content of a tar entry while the tar reader is positioned at it.
+type tarEntryReader struct {
+	tr      *tar.Reader
+	entry   int64
+	current *int64
+}
+
+func (r *tarEntryReader) Read(p []byte) (int, error) {
+	if r.entry != *r.current {
+		return 0, ErrEntryExpired
+	}
+	return r.tr.Read(p)
+}
+
+func (r *tarEntryReader) Close() error {
+	return nil
+}
+
+// ZipEntries returns a lazy stream of the entries of the zip archive zr.
+//
+// Opened entries that have not been closed are closed by the close handler of the stream.
+//
+// NOTE: There is no such thing in the Java standard library.
+func ZipEntries(zr *zip.Reader) Stream[ArchiveEntry] {
+	opened := map[*zipEntryReader]struct{}{}
+	files := sliceSource(zr.File)
+
+	s := newIteratorStream(func() (ArchiveEntry, bool, error) {
+		f, ok, _ := files()
+		if !ok {
+			return ArchiveEntry{}, false, nil
+		}
+		return ArchiveEntry{
+			Name:      f.Name,
+			Info:      f.FileInfo(),
+			ZipHeader: &f.FileHeader,
+			open: func() (io.ReadCloser, error) {
+				rc, err := f.Open()
+				if err != nil {
+					return nil, err
+				}
+				r := &zipEntryReader{ReadCloser: rc, opened: opened}
+				opened[r] = struct{}{}
+				return r, nil
+			},
+		}, true, nil
+	})
+	s.OnClose(func() {
+		for r := range opened {
+			_ = r.Close()
+		}
+	})
+	return s
+}
+
+// zipEntryReader is the content of an opened zip entry, which is tracked until it is closed,
+// so that the close handler of the stream only closes the entries that are still open.
+type zipEntryReader struct {
+	io.ReadCloser
+	opened map[*zipEntryReader]struct{}
+}
+
+func (r *zipEntryReader) Close() error {
+	delete(r.opened, r)
+	return r.ReadCloser.Close()
+}
+
+// ZipFileEntries opens the zip archive at path and returns a lazy stream of its entries.
+//
+// The archive is closed by the close handler of the stream, so the stream should be closed once it is no longer needed.
+func ZipFileEntries(path string) (Stream[ArchiveEntry], error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	return ZipEntries(&zr.Reader).OnClose(func() { _ = zr.Close() }), nil
+}
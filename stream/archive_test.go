@@ -0,0 +1,146 @@
+package stream_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+var archiveFiles = []struct{ name, content string }{
+	{"README.md", "# release"},
+	{"bin/tool", "binary"},
+	{"LICENSE", "MIT"},
+}
+
+func readEntry(t *testing.T, e stream.ArchiveEntry) string {
+	rc, err := e.Open()
+	require.NoError(t, err)
+	defer rc.Close()
+	content, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestTarEntries(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range archiveFiles {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: f.name, Mode: 0o644, Size: int64(len(f.content))}))
+		_, err := tw.Write([]byte(f.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	t.Run("filter and map", func(t *testing.T) {
+		s := stream.TarEntries(bytes.NewReader(buf.Bytes())).
+			Filter(func(e stream.ArchiveEntry) bool { return !strings.HasPrefix(e.Name, "bin/") })
+		contents := stream.Map(s, func(e stream.ArchiveEntry) string { return e.Name + ": " + readEntry(t, e) }).ToArray()
+
+		require.Equal(t, []string{"README.md: # release", "LICENSE: MIT"}, contents)
+	})
+
+	t.Run("headers", func(t *testing.T) {
+		e := stream.TarEntries(bytes.NewReader(buf.Bytes())).FindFirst()
+
+		require.Equal(t, "README.md", e.TarHeader.Name)
+		require.Equal(t, int64(9), e.Info.Size())
+		require.Nil(t, e.ZipHeader)
+	})
+
+	t.Run("expired entry", func(t *testing.T) {
+		entries := stream.TarEntries(bytes.NewReader(buf.Bytes())).ToArray()
+
+		for _, e := range entries {
+			_, err := e.Open()
+			require.ErrorIs(t, err, stream.ErrEntryExpired)
+		}
+
+		it := stream.TarEntries(bytes.NewReader(buf.Bytes())).Iterator()
+		first := it.Next()
+		rc, err := first.Open()
+		require.NoError(t, err)
+		_ = it.Next()
+		_, err = io.ReadAll(rc)
+		require.ErrorIs(t, err, stream.ErrEntryExpired)
+	})
+
+	t.Run("corrupted archive", func(t *testing.T) {
+		s := stream.TarEntries(bytes.NewReader(buf.Bytes()[:1100]))
+
+		require.Nil(t, s.ToArray())
+		require.Error(t, s.Err())
+	})
+
+	t.Run("closes the reader", func(t *testing.T) {
+		r := &closeRecorder{Reader: bytes.NewReader(buf.Bytes())}
+		s := stream.TarEntries(r)
+		require.Equal(t, int64(3), s.Count())
+
+		s.Close()
+		require.True(t, r.closed)
+	})
+}
+
+func TestZipEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "release.zip")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	zw := zip.NewWriter(f)
+	for _, file := range archiveFiles {
+		w, err := zw.Create(file.name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(file.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+
+	s, err := stream.ZipFileEntries(path)
+	require.NoError(t, err)
+	defer s.Close()
+
+	entries := s.ToArray()
+	require.Len(t, entries, 3)
+	require.Equal(t, "bin/tool", entries[1].ZipHeader.Name)
+	require.Equal(t, "MIT", readEntry(t, entries[2]))
+	require.Equal(t, "# release", readEntry(t, entries[0]))
+
+	_, err = stream.ZipFileEntries(filepath.Join(t.TempDir(), "missing.zip"))
+	require.Error(t, err)
+
+	t.Run("close closes the open entries", func(t *testing.T) {
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		zr, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+		require.NoError(t, err)
+		s := stream.ZipEntries(zr)
+		entries := s.ToArray()
+		closedByCaller, err := entries[0].Open()
+		require.NoError(t, err)
+		require.NoError(t, closedByCaller.Close())
+		open, err := entries[1].Open()
+		require.NoError(t, err)
+
+		s.Close()
+		_, err = open.Read(make([]byte, 1))
+		require.Error(t, err)
+	})
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
+}
@@ -0,0 +1,102 @@
+package stream
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// Bytes returns a lazy stream of the bytes read from r.
+//
+// Read errors stop the stream and are reported by Err.
+//
+// NOTE: There is no such thing in the Java standard library.
+// The closest thing is IntStream chars() of CharSequence.
+func Bytes(r io.Reader) Stream[byte] {
+	br := bufio.NewReader(r)
+	return newIteratorStream(func() (byte, bool, error) {
+		b, err := br.ReadByte()
+		if err == io.EOF {
+			return 0, false, nil
+		}
+		if err != nil {
+			return 0, false, err
+		}
+		return b, true, nil
+	})
+}
+
+// Chunks returns a lazy stream of chunks of size bytes read from r. The last chunk may be shorter.
+// Each chunk is a newly allocated slice, so the elements can be retained.
+//
+// Read errors stop the stream and are reported by Err.
+// It panics if size is not positive.
+//
+// NOTE: There is no such thing in the Java standard library.
+func Chunks(r io.Reader, size int) Stream[[]byte] {
+	if size <= 0 {
+		panic(fmt.Sprintf("stream: invalid chunk size %d", size))
+	}
+	return newIteratorStream(func() ([]byte, bool, error) {
+		chunk := make([]byte, size)
+		n, err := io.ReadFull(r, chunk)
+		if err == io.EOF {
+			return nil, false, nil
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return nil, false, err
+		}
+		return chunk[:n], true, nil
+	})
+}
+
+// AsReader returns an io.Reader that reads the concatenation of the elements of the stream.
+// The elements are requested from the stream only as they are needed.
+//
+// Reading from the returned reader consumes the stream. Once the stream is exhausted
+// the reader returns the error of the stream, or io.EOF if there is none.
+//
+// NOTE: There is no such thing in the Java standard library.
+func AsReader(stream Stream[[]byte]) io.Reader {
+	return &streamReader{stream: stream, it: stream.Iterator()}
+}
+
+type streamReader struct {
+	stream Stream[[]byte]
+	it     Iterator[[]byte]
+	buf    []byte
+}
+
+func (r *streamReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for len(r.buf) == 0 {
+		if !r.it.HasNext() {
+			if err := r.stream.Err(); err != nil {
+				return 0, err
+			}
+			return 0, io.EOF
+		}
+		r.buf = r.it.Next()
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// WriteTo writes the elements of the stream to w, one after the other.
+//
+// It is a terminal operation. It returns the number of bytes written
+// and the first write error or the error of the stream, if any.
+//
+// NOTE: There is no such thing in the Java standard library.
+func WriteTo[T ~[]byte | ~string](w io.Writer, stream Stream[T]) (int64, error) {
+	var written int64
+	err := forEachUntilErr(stream, func(t T) error {
+		n, err := w.Write([]byte(t))
+		written += int64(n)
+		return err
+	})
+	return written, err
+}
@@ -0,0 +1,92 @@
+package stream_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBytes(t *testing.T) {
+	s := stream.Bytes(strings.NewReader("a1b2")).Filter(func(b byte) bool { return b >= 'a' })
+
+	require.Equal(t, []byte("ab"), s.ToArray())
+
+	s = stream.Bytes(iotest.ErrReader(errors.New("read failed")))
+	require.Equal(t, int64(0), s.Count())
+	require.EqualError(t, s.Err(), "read failed")
+}
+
+func TestChunks(t *testing.T) {
+	s := stream.Chunks(iotest.OneByteReader(strings.NewReader("abcdefg")), 3)
+
+	require.Equal(t, [][]byte{[]byte("abc"), []byte("def"), []byte("g")}, s.ToArray())
+	require.Equal(t, [][]byte{}, stream.Chunks(strings.NewReader(""), 3).ToArray())
+	require.Panics(t, func() { stream.Chunks(strings.NewReader(""), 0) })
+}
+
+func TestAsReader(t *testing.T) {
+	t.Run("io.Copy", func(t *testing.T) {
+		s := stream.Map(stream.Of("hello", "", ", ", "world"), func(s string) []byte { return []byte(s) })
+
+		var buf bytes.Buffer
+		_, err := io.Copy(&buf, stream.AsReader(s))
+		require.NoError(t, err)
+		require.Equal(t, "hello, world", buf.String())
+	})
+
+	t.Run("small reads", func(t *testing.T) {
+		content, err := io.ReadAll(iotest.OneByteReader(stream.AsReader(stream.Of([]byte("abc"), []byte("de")))))
+
+		require.NoError(t, err)
+		require.Equal(t, "abcde", string(content))
+	})
+
+	t.Run("stream error", func(t *testing.T) {
+		s := stream.Chunks(iotest.ErrReader(errors.New("read failed")), 4)
+
+		_, err := io.ReadAll(stream.AsReader(s))
+		require.EqualError(t, err, "read failed")
+	})
+}
+
+func TestWriteTo(t *testing.T) {
+	t.Run("strings", func(t *testing.T) {
+		var buf bytes.Buffer
+		n, err := stream.WriteTo(&buf, stream.Of("a", "bc"))
+
+		require.NoError(t, err)
+		require.Equal(t, int64(3), n)
+		require.Equal(t, "abc", buf.String())
+	})
+
+	t.Run("compress and hash a chunk stream", func(t *testing.T) {
+		input := strings.Repeat("stream ", 1000)
+
+		var compressed bytes.Buffer
+		gz := gzip.NewWriter(&compressed)
+		_, err := stream.WriteTo(gz, stream.Chunks(strings.NewReader(input), 64))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+
+		gr, err := gzip.NewReader(&compressed)
+		require.NoError(t, err)
+		h := sha256.New()
+		_, err = stream.WriteTo(h, stream.Chunks(gr, 100))
+		require.NoError(t, err)
+		sum := sha256.Sum256([]byte(input))
+		require.Equal(t, sum[:], h.Sum(nil))
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		_, err := stream.WriteTo(failingWriter{}, stream.Of([]byte("a")))
+		require.EqualError(t, err, "write failed")
+	})
+}
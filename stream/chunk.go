@@ -0,0 +1,96 @@
+package stream
+
+import "fmt"
+
+// ChunkOptions configures ChunkWithOptions.
+type ChunkOptions[T any] struct {
+	// DropPartial drops the last chunk if it has less than n elements.
+	DropPartial bool
+	// Pad, if not nil, is used to fill the last chunk up to n elements.
+	Pad *T
+}
+
+// Chunk returns a lazy stream of the elements of the stream, grouped in chunks of n elements.
+// The last chunk may have less than n elements.
+// Each chunk is a newly allocated slice, so the elements can be retained.
+//
+// It panics if n is not positive.
+//
+// NOTE: There is no such thing in the Java standard library.
+func Chunk[T any](stream Stream[T], n int) Stream[[]T] {
+	return ChunkWithOptions(stream, n, ChunkOptions[T]{})
+}
+
+// ChunkWithOptions is like Chunk, but the last chunk is handled according to the given options.
+func ChunkWithOptions[T any](stream Stream[T], n int, opts ChunkOptions[T]) Stream[[]T] {
+	if n <= 0 {
+		panic(fmt.Sprintf("stream: invalid chunk size %d", n))
+	}
+
+	s := lazy(stream)
+	return deriveIteratorStream(s, func() ([]T, bool) {
+		chunk := make([]T, 0, n)
+		for len(chunk) < n {
+			el, ok := s.pull()
+			if !ok {
+				break
+			}
+			chunk = append(chunk, el)
+		}
+
+		switch {
+		case len(chunk) == n:
+			return chunk, true
+		case len(chunk) == 0 || opts.DropPartial || s.state.err != nil:
+			return nil, false
+		case opts.Pad != nil:
+			for len(chunk) < n {
+				chunk = append(chunk, *opts.Pad)
+			}
+		}
+		return chunk, true
+	})
+}
+
+// SlidingWindow returns a lazy stream of the windows of size consecutive elements of the stream,
+// each one starting step elements after the previous one.
+// If step is greater than size, the elements between the windows are skipped.
+// Only complete windows are returned, so the stream is empty if the stream has less than size elements.
+// Each window is a newly allocated slice, so the elements can be retained.
+//
+// It panics if size or step is not positive.
+//
+// NOTE: There is no such thing in the Java standard library.
+func SlidingWindow[T any](stream Stream[T], size, step int) Stream[[]T] {
+	if size <= 0 || step <= 0 {
+		panic(fmt.Sprintf("stream: invalid sliding window size %d and step %d", size, step))
+	}
+
+	s := lazy(stream)
+	var window []T
+	started := false
+	return deriveIteratorStream(s, func() ([]T, bool) {
+		if started {
+			if step < size {
+				window = window[step:]
+			} else {
+				window = window[:0]
+				for i := 0; i < step-size; i++ {
+					if _, ok := s.pull(); !ok {
+						return nil, false
+					}
+				}
+			}
+		}
+		started = true
+
+		for len(window) < size {
+			el, ok := s.pull()
+			if !ok {
+				return nil, false
+			}
+			window = append(window, el)
+		}
+		return append([]T{}, window...), true
+	})
+}
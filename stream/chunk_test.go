@@ -0,0 +1,93 @@
+package stream_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunk(t *testing.T) {
+	t.Run("short last chunk", func(t *testing.T) {
+		s := stream.Chunk(stream.Of(1, 2, 3, 4, 5), 2)
+
+		require.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, s.ToArray())
+	})
+
+	t.Run("exact", func(t *testing.T) {
+		require.Equal(t, [][]int{{1, 2}, {3, 4}}, stream.Chunk(stream.Of(1, 2, 3, 4), 2).ToArray())
+		require.Empty(t, stream.Chunk(stream.Of[int](), 2).ToArray())
+	})
+
+	t.Run("drop partial", func(t *testing.T) {
+		s := stream.ChunkWithOptions(stream.Of(1, 2, 3, 4, 5), 2, stream.ChunkOptions[int]{DropPartial: true})
+
+		require.Equal(t, [][]int{{1, 2}, {3, 4}}, s.ToArray())
+	})
+
+	t.Run("pad", func(t *testing.T) {
+		pad := 0
+		s := stream.ChunkWithOptions(stream.Of(1, 2, 3, 4, 5), 3, stream.ChunkOptions[int]{Pad: &pad})
+
+		require.Equal(t, [][]int{{1, 2, 3}, {4, 5, 0}}, s.ToArray())
+	})
+
+	t.Run("lazy", func(t *testing.T) {
+		lines := stream.Lines(strings.NewReader("a\nb\nc\nd\ne\n"))
+		pulled := 0
+		s := stream.Chunk(stream.Map(lines, func(line string) string {
+			pulled++
+			return line
+		}), 2)
+
+		require.Equal(t, []string{"a", "b"}, *s.FindFirst())
+		require.Equal(t, 2, pulled)
+	})
+
+	t.Run("close handlers and errors", func(t *testing.T) {
+		closed := false
+		s := stream.Chunk(stream.Lines(io.MultiReader(strings.NewReader("a\nb\nc\n"), iotest.ErrReader(errors.New("read failed")))).OnClose(func() { closed = true }), 2)
+
+		require.Nil(t, s.ToArray())
+		require.EqualError(t, s.Err(), "read failed")
+		require.True(t, closed)
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		require.Panics(t, func() { stream.Chunk(stream.Of(1), 0) })
+	})
+}
+
+func TestSlidingWindow(t *testing.T) {
+	t.Run("overlapping", func(t *testing.T) {
+		s := stream.SlidingWindow(stream.Of(1, 2, 3, 4, 5), 3, 1)
+
+		require.Equal(t, [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}, s.ToArray())
+	})
+
+	t.Run("step", func(t *testing.T) {
+		require.Equal(t, [][]int{{1, 2, 3}, {3, 4, 5}}, stream.SlidingWindow(stream.Of(1, 2, 3, 4, 5, 6), 3, 2).ToArray())
+		require.Equal(t, [][]int{{1, 2}, {3, 4}}, stream.SlidingWindow(stream.Of(1, 2, 3, 4, 5), 2, 2).ToArray())
+		require.Equal(t, [][]int{{1, 2}, {5, 6}}, stream.SlidingWindow(stream.Of(1, 2, 3, 4, 5, 6, 7), 2, 4).ToArray())
+	})
+
+	t.Run("shorter than the window", func(t *testing.T) {
+		require.Empty(t, stream.SlidingWindow(stream.Of(1, 2), 3, 1).ToArray())
+	})
+
+	t.Run("windows can be retained", func(t *testing.T) {
+		windows := stream.SlidingWindow(stream.Of(1, 2, 3, 4), 2, 1).ToArray()
+		windows[0][1] = 0
+
+		require.Equal(t, [][]int{{1, 0}, {2, 3}, {3, 4}}, windows)
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		require.Panics(t, func() { stream.SlidingWindow(stream.Of(1), 0, 1) })
+		require.Panics(t, func() { stream.SlidingWindow(stream.Of(1), 1, 0) })
+	})
+}
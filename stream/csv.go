@@ -0,0 +1,307 @@
+package stream
+
+import (
+	"encoding"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// CSVOptions configures ReadCSV and WriteCSV.
+type CSVOptions struct {
+	// Comma is the field delimiter. If it is 0, ',' is used.
+	Comma rune
+	// Comment, if not 0, is the comment character. Lines beginning with it are ignored by ReadCSV.
+	Comment rune
+	// NoHeader disables writing the header row in WriteCSV.
+	NoHeader bool
+	// UseCRLF makes WriteCSV use "\r\n" as the line terminator.
+	UseCRLF bool
+	// OnRowError, if not nil, is called by ReadCSV for each row that cannot be decoded
+	// and the row is skipped. Otherwise such a row stops the stream and the error is reported by Err.
+	OnRowError func(*CSVRowError)
+}
+
+// CSVRowError describes a row that could not be decoded by ReadCSV.
+type CSVRowError struct {
+	// Line is the line of the input on which the row starts (1-based).
+	Line int
+	// Column is the name of the column that could not be decoded, if the error is specific to a column.
+	Column string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *CSVRowError) Error() string {
+	if e.Column != "" {
+		return fmt.Sprintf("stream: CSV line %d, column %q: %v", e.Line, e.Column, e.Err)
+	}
+	return fmt.Sprintf("stream: CSV line %d: %v", e.Line, e.Err)
+}
+
+func (e *CSVRowError) Unwrap() error {
+	return e.Err
+}
+
+// ReadCSV returns a lazy stream of the rows read from r.
+//
+// If T is []string the rows are returned as they are, including the header row, if any.
+// If T is a struct, the first row is treated as a header and each subsequent row is decoded into a T.
+// Each field is filled from the column whose name is given by its `csv:"column"` tag, or by its name if there is no tag.
+// Fields tagged with `csv:"-"`, unexported fields and columns without a matching field are ignored.
+// Supported field types are strings, booleans, numbers and types implementing encoding.TextUnmarshaler.
+// Empty cells leave the field with its zero value.
+//
+// NOTE: There is no such thing in the Java standard library.
+func ReadCSV[T any](r io.Reader, opts CSVOptions) Stream[T] {
+	cr := csv.NewReader(r)
+	if opts.Comma != 0 {
+		cr.Comma = opts.Comma
+	}
+	cr.Comment = opts.Comment
+
+	var decode func(record []string) (T, *CSVRowError)
+	return newIteratorStream(func() (T, bool, error) {
+		var zero T
+		if decode == nil {
+			d, err := newCSVDecoder[T](cr)
+			if err != nil {
+				return zero, false, err
+			}
+			decode = d
+		}
+
+		for {
+			record, err := cr.Read()
+			if err == io.EOF {
+				return zero, false, nil
+			}
+			var rowErr *CSVRowError
+			if err != nil {
+				var parseErr *csv.ParseError
+				if !errors.As(err, &parseErr) || !errors.Is(parseErr.Err, csv.ErrFieldCount) {
+					return zero, false, err
+				}
+				rowErr = &CSVRowError{Line: parseErr.StartLine, Err: parseErr.Err}
+			}
+
+			var el T
+			if rowErr == nil {
+				el, rowErr = decode(record)
+				if rowErr != nil {
+					rowErr.Line, _ = cr.FieldPos(0)
+				}
+			}
+			if rowErr == nil {
+				return el, true, nil
+			}
+			if opts.OnRowError == nil {
+				return zero, false, rowErr
+			}
+			opts.OnRowError(rowErr)
+		}
+	})
+}
+
+// newCSVDecoder returns a function that decodes a CSV record into a T.
+// If T is a struct, the header is read from r.
+func newCSVDecoder[T any](r *csv.Reader) (func([]string) (T, *CSVRowError), error) {
+	var zero T
+	if _, ok := any(zero).([]string); ok {
+		return func(record []string) (T, *CSVRowError) {
+			return any(record).(T), nil
+		}, nil
+	}
+
+	fields, err := csvFields(reflect.TypeOf(zero))
+	if err != nil {
+		return nil, err
+	}
+	header, err := r.Read()
+	if err == io.EOF {
+		header = nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	columns := make([]*csvField, len(header))
+	for i, name := range header {
+		for j := range fields {
+			if fields[j].name == name {
+				columns[i] = &fields[j]
+			}
+		}
+	}
+
+	return func(record []string) (T, *CSVRowError) {
+		var el T
+		v := reflect.ValueOf(&el).Elem()
+		for i, value := range record {
+			if i >= len(columns) || columns[i] == nil || value == "" {
+				continue
+			}
+			if err := setCSVField(v.Field(columns[i].index), value); err != nil {
+				return el, &CSVRowError{Column: columns[i].name, Err: err}
+			}
+		}
+		return el, nil
+	}, nil
+}
+
+// WriteCSV writes the elements of the stream to w as CSV.
+//
+// If T is []string the elements are written as they are.
+// If T is a struct, a header row is written first (unless disabled by the options), followed by one row for each element,
+// using the same mapping between fields and columns as ReadCSV.
+// Fields implementing encoding.TextMarshaler are written using it.
+//
+// It is a terminal operation. It returns the first encoding or write error, or the error of the stream.
+//
+// NOTE: There is no such thing in the Java standard library.
+func WriteCSV[T any](w io.Writer, stream Stream[T], opts CSVOptions) error {
+	cw := csv.NewWriter(w)
+	if opts.Comma != 0 {
+		cw.Comma = opts.Comma
+	}
+	cw.UseCRLF = opts.UseCRLF
+
+	var encode func(T) ([]string, error)
+	var zero T
+	if _, ok := any(zero).([]string); ok {
+		encode = func(t T) ([]string, error) {
+			return any(t).([]string), nil
+		}
+	} else {
+		fields, err := csvFields(reflect.TypeOf(zero))
+		if err != nil {
+			return err
+		}
+		if !opts.NoHeader {
+			header := make([]string, 0, len(fields))
+			for _, f := range fields {
+				header = append(header, f.name)
+			}
+			if err := cw.Write(header); err != nil {
+				return err
+			}
+		}
+		encode = func(t T) ([]string, error) {
+			v := reflect.ValueOf(t)
+			record := make([]string, 0, len(fields))
+			for _, f := range fields {
+				value, err := formatCSVField(v.Field(f.index))
+				if err != nil {
+					return nil, fmt.Errorf("stream: CSV column %q: %w", f.name, err)
+				}
+				record = append(record, value)
+			}
+			return record, nil
+		}
+	}
+
+	err := forEachUntilErr(stream, func(t T) error {
+		record, err := encode(t)
+		if err != nil {
+			return err
+		}
+		return cw.Write(record)
+	})
+	cw.Flush()
+	if err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// csvField describes a struct field that is mapped to a CSV column.
+type csvField struct {
+	name  string
+	index int
+}
+
+// csvFields returns the fields of the struct type t that are mapped to CSV columns.
+func csvFields(t reflect.Type) ([]csvField, error) {
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("stream: CSV rows can only be mapped to []string or structs, not %v", t)
+	}
+
+	var fields []csvField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("csv"); ok {
+			if tag == "-" {
+				continue
+			}
+			name = tag
+		}
+		fields = append(fields, csvField{name: name, index: i})
+	}
+	return fields, nil
+}
+
+func setCSVField(v reflect.Value, value string) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(value))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported field type %v", v.Type())
+	}
+	return nil
+}
+
+func formatCSVField(v reflect.Value) (string, error) {
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	default:
+		return "", fmt.Errorf("unsupported field type %v", v.Type())
+	}
+}
@@ -0,0 +1,128 @@
+package stream_test
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+type product struct {
+	SKU     string    `csv:"sku"`
+	Price   float64   `csv:"price"`
+	Stock   int       `csv:"stock"`
+	Added   time.Time `csv:"added"`
+	Note    string    `csv:"-"`
+	Visible bool
+}
+
+func TestReadCSV(t *testing.T) {
+	input := `stock,sku,price,ignored,Visible,added
+3,A-1,9.99,x,true,2022-12-22T00:00:00Z
+0,B-2,15,y,false,
+`
+
+	t.Run("raw rows", func(t *testing.T) {
+		s := stream.ReadCSV[[]string](strings.NewReader("a,b\n1,2\n"), stream.CSVOptions{})
+
+		require.Equal(t, [][]string{{"a", "b"}, {"1", "2"}}, s.ToArray())
+	})
+
+	t.Run("structs", func(t *testing.T) {
+		s := stream.ReadCSV[product](strings.NewReader(input), stream.CSVOptions{})
+
+		require.Equal(t, []product{
+			{SKU: "A-1", Price: 9.99, Stock: 3, Added: time.Date(2022, 12, 22, 0, 0, 0, 0, time.UTC), Visible: true},
+			{SKU: "B-2", Price: 15},
+		}, s.ToArray())
+		require.NoError(t, s.Err())
+	})
+
+	t.Run("in a pipeline", func(t *testing.T) {
+		s := stream.ReadCSV[product](strings.NewReader(input), stream.CSVOptions{}).
+			Filter(func(p product) bool { return p.Stock > 0 })
+
+		require.Equal(t, []string{"A-1"}, stream.Map(s, func(p product) string { return p.SKU }).ToArray())
+	})
+
+	t.Run("options", func(t *testing.T) {
+		s := stream.ReadCSV[product](strings.NewReader("# export\nsku;stock\nA-1;3\n"), stream.CSVOptions{Comma: ';', Comment: '#'})
+
+		require.Equal(t, []product{{SKU: "A-1", Stock: 3}}, s.ToArray())
+	})
+
+	t.Run("row error stops the stream", func(t *testing.T) {
+		s := stream.ReadCSV[product](strings.NewReader("sku,stock\nA-1,3\nB-2,many\n"), stream.CSVOptions{})
+
+		require.Nil(t, s.ToArray())
+		var rowErr *stream.CSVRowError
+		require.ErrorAs(t, s.Err(), &rowErr)
+		require.Equal(t, 3, rowErr.Line)
+		require.Equal(t, "stock", rowErr.Column)
+		require.ErrorIs(t, s.Err(), strconv.ErrSyntax)
+	})
+
+	t.Run("row errors reported to handler", func(t *testing.T) {
+		var rowErrs []*stream.CSVRowError
+		s := stream.ReadCSV[product](strings.NewReader("sku,stock\nA-1,many\nB-2,2,extra\nC-3,3\n"), stream.CSVOptions{
+			OnRowError: func(err *stream.CSVRowError) { rowErrs = append(rowErrs, err) },
+		})
+
+		require.Equal(t, []product{{SKU: "C-3", Stock: 3}}, s.ToArray())
+		require.Len(t, rowErrs, 2)
+		require.Equal(t, 2, rowErrs[0].Line)
+		require.Equal(t, 3, rowErrs[1].Line)
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		s := stream.ReadCSV[int](strings.NewReader("a\n1\n"), stream.CSVOptions{})
+
+		require.Equal(t, int64(0), s.Count())
+		require.Error(t, s.Err())
+	})
+}
+
+func TestWriteCSV(t *testing.T) {
+	s := stream.Of(
+		product{SKU: "A-1", Price: 9.99, Stock: 3, Added: time.Date(2022, 12, 22, 0, 0, 0, 0, time.UTC), Note: "hidden", Visible: true},
+		product{SKU: "B,2", Price: 15},
+	)
+
+	t.Run("structs", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, stream.WriteCSV(&buf, s, stream.CSVOptions{}))
+
+		require.Equal(t, `sku,price,stock,added,Visible
+A-1,9.99,3,2022-12-22T00:00:00Z,true
+"B,2",15,0,0001-01-01T00:00:00Z,false
+`, buf.String())
+	})
+
+	t.Run("no header", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, stream.WriteCSV(&buf, s.Limit(1), stream.CSVOptions{NoHeader: true, Comma: ';'}))
+
+		require.Equal(t, "A-1;9.99;3;2022-12-22T00:00:00Z;true\n", buf.String())
+	})
+
+	t.Run("raw rows", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, stream.WriteCSV(&buf, stream.Of([]string{"a", "b"}, []string{"1", "2"}), stream.CSVOptions{UseCRLF: true}))
+
+		require.Equal(t, "a,b\r\n1,2\r\n", buf.String())
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, stream.WriteCSV(&buf, s, stream.CSVOptions{}))
+
+		read := stream.ReadCSV[product](&buf, stream.CSVOptions{}).ToArray()
+		require.Len(t, read, 2)
+		require.Equal(t, "B,2", read[1].SKU)
+		require.Empty(t, read[0].Note)
+	})
+}
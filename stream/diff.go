@@ -0,0 +1,77 @@
+package stream
+
+// ChangeKind is the kind of a Change.
+type ChangeKind int
+
+const (
+	// Added means that the element is only in the new stream.
+	Added ChangeKind = iota + 1
+	// Removed means that the element is only in the old stream.
+	Removed
+	// Changed means that the element is in both streams, but the old and the new one are not equal.
+	Changed
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	}
+	return "unknown"
+}
+
+// Change is an element of the stream returned by DiffSorted.
+type Change[T any] struct {
+	Kind ChangeKind
+	// Old is the element of the old stream. It is nil if the element was added.
+	Old *T
+	// New is the element of the new stream. It is nil if the element was removed.
+	New *T
+}
+
+// DiffSorted returns a lazy stream of the changes between oldStream and newStream, both sorted by the unique keys returned by key.
+// Elements with the same key in both streams are compared with equal, and are a Changed change if they are not equal.
+// The changes are in key order.
+//
+// Both streams are walked once, at the same time, and only their current elements are kept in memory.
+// Closing the stream closes both streams, and the errors of both are reported by Err.
+// The stream is in Recover mode if either stream is, and then a panic of key or equal is reported by Err.
+//
+// NOTE: There is no such thing in the Java standard library.
+func DiffSorted[T any, K Ordered](oldStream Stream[T], newStream Stream[T], key func(T) K, equal func(T, T) bool) Stream[Change[T]] {
+	var (
+		started        bool
+		oldEl, newEl   T
+		hasOld, hasNew bool
+	)
+	return zipped(oldStream, newStream, func(lo *IteratorStream[T], ln *IteratorStream[T]) (Change[T], bool) {
+		if !started {
+			started = true
+			oldEl, hasOld = lo.pull()
+			newEl, hasNew = ln.pull()
+		}
+
+		for hasOld || hasNew {
+			o, n := oldEl, newEl
+			switch {
+			case !hasNew || (hasOld && key(o) < key(n)):
+				oldEl, hasOld = lo.pull()
+				return Change[T]{Kind: Removed, Old: &o}, true
+			case !hasOld || key(n) < key(o):
+				newEl, hasNew = ln.pull()
+				return Change[T]{Kind: Added, New: &n}, true
+			}
+
+			oldEl, hasOld = lo.pull()
+			newEl, hasNew = ln.pull()
+			if !equal(o, n) {
+				return Change[T]{Kind: Changed, Old: &o, New: &n}, true
+			}
+		}
+		return Change[T]{}, false
+	})
+}
@@ -0,0 +1,78 @@
+package stream_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+type record struct {
+	ID    int
+	Email string
+}
+
+func recordID(r record) int       { return r.ID }
+func sameRecord(a, b record) bool { return a == b }
+
+func describeChange(c stream.Change[record]) string {
+	switch c.Kind {
+	case stream.Added:
+		return fmt.Sprintf("+%d", c.New.ID)
+	case stream.Removed:
+		return fmt.Sprintf("-%d", c.Old.ID)
+	}
+	return fmt.Sprintf("~%d %s->%s", c.New.ID, c.Old.Email, c.New.Email)
+}
+
+func TestDiffSorted(t *testing.T) {
+	t.Run("changes", func(t *testing.T) {
+		yesterday := stream.Of(record{1, "a@x"}, record{2, "b@x"}, record{4, "d@x"}, record{5, "e@x"})
+		today := stream.Of(record{0, "z@x"}, record{2, "b@y"}, record{3, "c@x"}, record{4, "d@x"}, record{6, "f@x"})
+
+		changes := stream.Map(stream.DiffSorted(yesterday, today, recordID, sameRecord), describeChange).ToArray()
+
+		require.Equal(t, []string{"+0", "-1", "~2 b@x->b@y", "+3", "-5", "+6"}, changes)
+		require.Equal(t, "changed", stream.Changed.String())
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		s := stream.DiffSorted(stream.Of(record{1, "a"}), stream.Of(record{1, "a"}), recordID, sameRecord)
+
+		require.Empty(t, s.ToArray())
+	})
+
+	t.Run("lazy", func(t *testing.T) {
+		old := stream.Lines(strings.NewReader("a\nb\nc\n"))
+		s := stream.DiffSorted(old, stream.Lines(strings.NewReader("b\n")), func(s string) string { return s }, func(a, b string) bool { return a == b })
+
+		c := s.FindFirst()
+		require.Equal(t, stream.Removed, c.Kind)
+		require.Equal(t, "a", *c.Old)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		failing := stream.Lines(io.MultiReader(strings.NewReader("a\n"), iotest.ErrReader(errors.New("read failed"))))
+		s := stream.DiffSorted(stream.Of("a", "b"), failing, func(s string) string { return s }, func(a, b string) bool { return a == b })
+
+		require.Nil(t, s.ToArray())
+		require.EqualError(t, s.Err(), "read failed")
+	})
+
+	t.Run("recover", func(t *testing.T) {
+		yesterday := stream.Of(record{1, "a@x"}, record{2, "b@x"}).Recover()
+		today := stream.Of(record{1, "a@x"}, record{2, "b@y"})
+		s := stream.DiffSorted(yesterday, today, recordID, func(a, b record) bool {
+			boomOn(a.ID, 2)
+			return a == b
+		})
+
+		require.Nil(t, s.ToArray())
+		requireBoom(t, s.Err())
+	})
+}
@@ -0,0 +1,125 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"time"
+)
+
+// maxCapturedStderr is the maximum number of bytes of the standard error of a command that are captured by CommandLines.
+const maxCapturedStderr = 64 << 10
+
+// DefaultCommandCloseTimeout is the maximum time that closing a stream returned by CommandLines waits for the command,
+// unless configured otherwise.
+const DefaultCommandCloseTimeout = 5 * time.Second
+
+// CommandOptions configures CommandLinesWithOptions.
+type CommandOptions struct {
+	// MergeStderr makes the lines written by the command to its standard error part of the stream.
+	//
+	// Otherwise, if cmd.Stderr is set it is left as it is, so that the standard error can be consumed separately.
+	// If it is not set, the beginning of the standard error is captured
+	// and is available in the Stderr field of the *exec.ExitError reported by the stream.
+	MergeStderr bool
+	// Lines configures how the output is split into elements.
+	Lines LinesOptions
+	// CloseTimeout is the maximum time that closing the stream waits for the command after killing it.
+	// If it is 0, DefaultCommandCloseTimeout is used.
+	CloseTimeout time.Duration
+}
+
+// CommandLines starts cmd and returns a lazy stream of the lines written by it to its standard output.
+//
+// If the command exits with a non-zero status, the stream stops with an *exec.ExitError, which is reported by Err.
+// If ctx is done before the command exits, the command is killed and the stream stops with ctx.Err().
+// Closing the stream kills the command (if it is still running) and waits for it to exit,
+// so the stream should be closed once it is no longer needed.
+// Only the command itself is killed - if it has started child processes that keep its standard output open,
+// the stream ends only when they exit, and closing it stops waiting for them after DefaultCommandCloseTimeout.
+//
+// cmd.Stdout must not be set, because the standard output is what the stream is read from.
+// It is an error to call it with a command whose Stdout is set, or whose Stderr is set when MergeStderr is true.
+//
+// NOTE: In Java the closest thing is Process.inputReader().lines(), which does not take care of the process.
+func CommandLines(ctx context.Context, cmd *exec.Cmd) (Stream[string], error) {
+	return CommandLinesWithOptions(ctx, cmd, CommandOptions{})
+}
+
+// CommandLinesWithOptions is like CommandLines, but it runs the command according to the given options.
+func CommandLinesWithOptions(ctx context.Context, cmd *exec.Cmd, opts CommandOptions) (Stream[string], error) {
+	if cmd.Stdout != nil {
+		return nil, errors.New("stream: command Stdout already set")
+	}
+	if opts.MergeStderr && cmd.Stderr != nil {
+		return nil, errors.New("stream: command Stderr already set")
+	}
+	if opts.CloseTimeout == 0 {
+		opts.CloseTimeout = DefaultCommandCloseTimeout
+	}
+
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	var stderr *prefixBuffer
+	if opts.MergeStderr {
+		cmd.Stderr = pw
+	} else if cmd.Stderr == nil {
+		stderr = &prefixBuffer{max: maxCapturedStderr}
+		cmd.Stderr = stderr
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := cmd.Wait()
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		var exitErr *exec.ExitError
+		if stderr != nil && errors.As(err, &exitErr) {
+			exitErr.Stderr = stderr.buf
+		}
+		_ = pw.CloseWithError(err)
+	}()
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = cmd.Process.Kill()
+		case <-done:
+		}
+	}()
+
+	return LinesWithOptions(pr, opts.Lines).OnClose(func() {
+		_ = cmd.Process.Kill()
+		_ = pr.Close()
+		// cmd.Wait also waits for the output to be copied to pw,
+		// which does not end while a child process of the command keeps its standard output open.
+		timer := time.NewTimer(opts.CloseTimeout)
+		defer timer.Stop()
+		select {
+		case <-done:
+		case <-timer.C:
+		}
+	}), nil
+}
+
+// prefixBuffer is an io.Writer that keeps the first max bytes written to it.
+type prefixBuffer struct {
+	buf []byte
+	max int
+}
+
+func (b *prefixBuffer) Write(p []byte) (int, error) {
+	if n := b.max - len(b.buf); n > 0 {
+		if n > len(p) {
+			n = len(p)
+		}
+		b.buf = append(b.buf, p[:n]...)
+	}
+	return len(p), nil
+}
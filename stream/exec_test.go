@@ -0,0 +1,116 @@
+package stream_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+func shell(t *testing.T, script string) *exec.Cmd {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh is not available")
+	}
+	return exec.Command(sh, "-c", script)
+}
+
+func TestCommandLines(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("stdout lines", func(t *testing.T) {
+		s, err := stream.CommandLines(ctx, shell(t, "printf 'fix: a\\nfeat: b\\nfix: c\\n'; echo oops >&2"))
+		require.NoError(t, err)
+		defer s.Close()
+
+		fixes := s.Filter(func(line string) bool { return strings.HasPrefix(line, "fix:") }).ToArray()
+		require.Equal(t, []string{"fix: a", "fix: c"}, fixes)
+		require.NoError(t, s.Err())
+	})
+
+	t.Run("merged stderr", func(t *testing.T) {
+		s, err := stream.CommandLinesWithOptions(ctx, shell(t, "echo out; sleep 0.1; echo err >&2"), stream.CommandOptions{MergeStderr: true})
+		require.NoError(t, err)
+		defer s.Close()
+
+		require.Equal(t, []string{"out", "err"}, s.ToArray())
+	})
+
+	t.Run("separate stderr", func(t *testing.T) {
+		var stderr bytes.Buffer
+		cmd := shell(t, "echo out; echo err >&2")
+		cmd.Stderr = &stderr
+		s, err := stream.CommandLines(ctx, cmd)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"out"}, s.ToArray())
+		s.Close()
+		require.Equal(t, "err\n", stderr.String())
+	})
+
+	t.Run("non-zero exit status", func(t *testing.T) {
+		s, err := stream.CommandLines(ctx, shell(t, "echo partial; echo 'fatal: not a git repository' >&2; exit 3"))
+		require.NoError(t, err)
+		defer s.Close()
+
+		require.Nil(t, s.ToArray())
+		var exitErr *exec.ExitError
+		require.ErrorAs(t, s.Err(), &exitErr)
+		require.Equal(t, 3, exitErr.ExitCode())
+		require.Equal(t, "fatal: not a git repository\n", string(exitErr.Stderr))
+	})
+
+	t.Run("close kills the process", func(t *testing.T) {
+		cmd := shell(t, "while true; do echo tick; done")
+		s, err := stream.CommandLines(ctx, cmd)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"tick", "tick"}, s.Limit(2).ToArray())
+		s.Close()
+		require.NotNil(t, cmd.ProcessState)
+		require.False(t, cmd.ProcessState.Success())
+	})
+
+	t.Run("context cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		s, err := stream.CommandLines(ctx, shell(t, "echo start; exec sleep 10"))
+		require.NoError(t, err)
+		defer s.Close()
+
+		require.Equal(t, int64(0), s.Count())
+		require.True(t, errors.Is(s.Err(), context.DeadlineExceeded))
+	})
+
+	t.Run("close does not wait for child processes forever", func(t *testing.T) {
+		s, err := stream.CommandLinesWithOptions(ctx, shell(t, "sleep 10 & echo started"), stream.CommandOptions{
+			CloseTimeout: 100 * time.Millisecond,
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, "started", *s.FindFirst())
+		start := time.Now()
+		s.Close()
+		require.Less(t, time.Since(start), 5*time.Second)
+	})
+
+	t.Run("Stdout already set", func(t *testing.T) {
+		cmd := shell(t, "echo hello")
+		cmd.Stdout = &bytes.Buffer{}
+
+		_, err := stream.CommandLines(ctx, cmd)
+		require.Error(t, err)
+		require.Nil(t, cmd.Process)
+	})
+
+	t.Run("start error", func(t *testing.T) {
+		_, err := stream.CommandLines(ctx, exec.Command("/nonexistent/command"))
+		require.Error(t, err)
+	})
+}
@@ -12,6 +12,27 @@ package stream
 //
 //	java: <R> Stream<R> map(Function<? super T,? extends R> mapper)
 func Map[T any, R any](stream Stream[T], mapper func(T) R) Stream[R] {
+	if s, ok := stream.(*SliceStream[T]); ok {
+		mapped := make([]R, 0, len(s.elements))
+		err := s.each(func(el T) bool {
+			mapped = append(mapped, mapper(el))
+			return true
+		})
+		return deriveSliceStream(s, mapped, err)
+	}
+	if s, ok := stream.(*IteratorStream[T]); ok {
+		var i int64
+		return deriveIteratorStream(s, func() (R, bool) {
+			var mapped R
+			el, ok := s.pull()
+			if !ok || !s.call(i, func() { mapped = mapper(el) }) {
+				return mapped, false
+			}
+			i++
+			return mapped, true
+		})
+	}
+
 	mapped := make([]R, 0, stream.Count())
 	stream.ForEach(func(el T) {
 		mapped = append(mapped, mapper(el))
@@ -29,6 +50,53 @@ func Map[T any, R any](stream Stream[T], mapper func(T) R) Stream[R] {
 //
 //	java: <R> Stream<R> flatMap(Function<? super T,? extends Stream<? extends R>> mapper)
 func FlatMap[T any, R any](stream Stream[T], mapper func(T) Stream[R]) Stream[R] {
+	if s, ok := stream.(*SliceStream[T]); ok {
+		newEl := make([]R, 0, len(s.elements))
+		var innerErr error
+		err := s.each(func(el T) bool {
+			inner := mapper(el)
+			newEl = append(newEl, inner.ToArray()...)
+			innerErr = inner.Err()
+			inner.Close()
+			return innerErr == nil
+		})
+		if err == nil {
+			err = innerErr
+		}
+		return deriveSliceStream(s, newEl, err)
+	}
+	if s, ok := stream.(*IteratorStream[T]); ok {
+		var (
+			i       int64
+			current Stream[R]
+			it      Iterator[R]
+		)
+		s.state.closeHandlers = append(s.state.closeHandlers, func() {
+			if current != nil {
+				current.Close()
+			}
+		})
+		return deriveIteratorStream(s, func() (R, bool) {
+			for it == nil || !it.HasNext() {
+				if current != nil {
+					current.Close()
+					if err := current.Err(); err != nil {
+						s.state.fail(err)
+					}
+					current = nil
+				}
+				el, ok := s.pull()
+				if !ok || !s.call(i, func() { current = mapper(el) }) {
+					var zero R
+					return zero, false
+				}
+				i++
+				it = current.Iterator()
+			}
+			return it.Next(), true
+		})
+	}
+
 	streams := make([]Stream[R], 0, stream.Count())
 	stream.ForEach(func(t T) {
 		streams = append(streams, mapper(t))
@@ -55,6 +123,10 @@ func Collect[T any, R any](stream Stream[T], supplier Supplier[R], accumulator B
 	stream.ForEach(func(t T) {
 		accumulator(t, r)
 	})
+	if stream.Err() != nil {
+		var zero R
+		return zero
+	}
 
 	return r
 
@@ -90,8 +162,72 @@ func ReduceWithIdentityAndCombiner[T any, U any](stream Stream[T], identity U, a
 	stream.ForEach(func(t T) {
 		res = accumulator(res, t)
 	})
+	if stream.Err() != nil {
+		var zero U
+		return zero
+	}
 
 	// TODO(asankov): use combiner if the stream is parallel
 
 	return res
 }
+
+// ScanOptions configures ScanWithOptions.
+type ScanOptions struct {
+	// Exclusive makes the stream emit the accumulated value before each element is accumulated,
+	// starting with the identity and without the accumulation of the last element.
+	Exclusive bool
+}
+
+// Scan returns a lazy stream of the intermediate results of the reduction of the elements of this stream,
+// using the provided identity and accumulation function.
+// Each element of the returned stream is the accumulated value after the corresponding element of this stream,
+// so the last one is the result of ReduceWithIdentityAndCombiner.
+//
+// NOTE: There is no such thing in the Java standard library.
+func Scan[T any, U any](stream Stream[T], identity U, accumulator func(U, T) U) Stream[U] {
+	return ScanWithOptions(stream, identity, accumulator, ScanOptions{})
+}
+
+// ScanWithOptions is like Scan, but with the given options.
+func ScanWithOptions[T any, U any](stream Stream[T], identity U, accumulator func(U, T) U, opts ScanOptions) Stream[U] {
+	s := lazy(stream)
+	acc := identity
+	var i int64
+	return deriveIteratorStream(s, func() (U, bool) {
+		el, ok := s.pull()
+		if !ok {
+			var zero U
+			return zero, false
+		}
+
+		prev := acc
+		if !s.call(i, func() { acc = accumulator(acc, el) }) {
+			var zero U
+			return zero, false
+		}
+		i++
+		if opts.Exclusive {
+			return prev, true
+		}
+		return acc, true
+	})
+}
+
+// forEachUntilErr performs an action for each element of the stream until the action returns an error.
+// It is the building block of the terminal operations that write the elements somewhere.
+//
+// It returns the error of the action or the error of the stream, if any.
+// Like with any other terminal operation, a failed stream is closed.
+func forEachUntilErr[T any](stream Stream[T], action func(T) error) error {
+	var err error
+	stream.AnyMatch(func(t T) bool {
+		err = action(t)
+		return err != nil
+	})
+	if err != nil {
+		stream.Close()
+		return err
+	}
+	return stream.Err()
+}
@@ -1,9 +1,11 @@
 package stream_test
 
 import (
+	"errors"
 	"strconv"
 	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/asankov/go-streams/stream"
 	"github.com/stretchr/testify/require"
@@ -38,6 +40,25 @@ func TestFlatMap(t *testing.T) {
 	require.True(t, mapped.AnyMatch(func(s string) bool { return s == "111" }))
 	require.True(t, mapped.AnyMatch(func(s string) bool { return s == "222" }))
 	require.True(t, mapped.AnyMatch(func(s string) bool { return s == "333" }))
+
+	t.Run("inner streams are closed", func(t *testing.T) {
+		var closed int
+		mapped := stream.FlatMap(stream.Of("a\nb", "c"), func(text string) stream.Stream[string] {
+			return stream.Lines(strings.NewReader(text)).OnClose(func() { closed++ })
+		})
+
+		require.Equal(t, []string{"a", "b", "c"}, mapped.ToArray())
+		require.Equal(t, 2, closed)
+	})
+
+	t.Run("inner errors are reported", func(t *testing.T) {
+		mapped := stream.FlatMap(stream.Of(1, 2), func(int) stream.Stream[string] {
+			return stream.Lines(iotest.ErrReader(errors.New("read failed")))
+		})
+
+		require.Nil(t, mapped.ToArray())
+		require.EqualError(t, mapped.Err(), "read failed")
+	})
 }
 
 func TestReduce(t *testing.T) {
@@ -82,3 +103,33 @@ type simpleCollector[T any, A any, R any] struct {
 func (s *simpleCollector[T, A, R]) Supplier() stream.Supplier[R]         { return s.supplier }
 func (s *simpleCollector[T, A, R]) Accumulator() stream.BiConsumer[T, R] { return s.accumulator }
 func (s *simpleCollector[T, A, R]) Combiner() stream.BiConsumer[R, R]    { return s.combiner }
+
+func TestScan(t *testing.T) {
+	type entry struct {
+		Amount  int
+		Balance int
+	}
+	ledger := stream.Of(100, -30, 50, -20)
+
+	balances := stream.Scan(ledger, entry{}, func(prev entry, amount int) entry {
+		return entry{Amount: amount, Balance: prev.Balance + amount}
+	}).ToArray()
+	require.Equal(t, []entry{{100, 100}, {-30, 70}, {50, 120}, {-20, 100}}, balances)
+
+	t.Run("exclusive", func(t *testing.T) {
+		opening := stream.ScanWithOptions(stream.Of(100, -30, 50), 0, func(a, b int) int { return a + b }, stream.ScanOptions{Exclusive: true})
+
+		require.Equal(t, []int{0, 100, 70}, opening.ToArray())
+	})
+
+	t.Run("lazy", func(t *testing.T) {
+		calls := 0
+		s := stream.Scan(stream.Lines(strings.NewReader("a\nb\nc\n")), "", func(acc, line string) string {
+			calls++
+			return acc + line
+		})
+
+		require.Equal(t, "ab", *s.Skip(1).FindFirst())
+		require.Equal(t, 2, calls)
+	})
+}
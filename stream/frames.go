@@ -0,0 +1,177 @@
+package stream
+
+import (
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+)
+
+// DefaultMaxFrameSize is the maximum size of a frame read by ReadFrames, unless configured otherwise.
+const DefaultMaxFrameSize = 64 << 20
+
+var (
+	// ErrFrameChecksum is reported by ReadFramesWithOptions when the checksum of a frame does not match its content.
+	ErrFrameChecksum = errors.New("stream: frame checksum mismatch")
+	// ErrFrameTooLarge is returned by WriteFrames and reported by ReadFrames when a frame is larger than the maximum frame size.
+	ErrFrameTooLarge = errors.New("stream: frame too large")
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// FramesOptions configures WriteFramesWithOptions and ReadFramesWithOptions.
+// The same options must be used for writing and reading the frames.
+type FramesOptions struct {
+	// CRC adds a CRC-32C checksum after each frame, which is verified when the frame is read.
+	CRC bool
+	// MaxFrameSize is the maximum size of a frame that can be written or read.
+	// If it is 0, DefaultMaxFrameSize is used.
+	// Frames can never be larger than math.MaxUint32 bytes, the maximum length of the prefix.
+	MaxFrameSize int
+}
+
+// maxFrameSize returns the maximum size of a frame according to the options.
+func (o FramesOptions) maxFrameSize() uint64 {
+	if o.MaxFrameSize == 0 {
+		return DefaultMaxFrameSize
+	}
+	if uint64(o.MaxFrameSize) > math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return uint64(o.MaxFrameSize)
+}
+
+// WriteGob writes the elements of the stream to w using a single gob.Encoder.
+// The elements can be read back with ReadGob.
+//
+// It is a terminal operation. It returns the first encoding or write error, or the error of the stream.
+//
+// NOTE: There is no such thing in the Java standard library.
+func WriteGob[T any](w io.Writer, stream Stream[T]) error {
+	enc := gob.NewEncoder(w)
+	return forEachUntilErr(stream, func(t T) error {
+		return enc.Encode(t)
+	})
+}
+
+// ReadGob returns a lazy stream of the elements written to r by WriteGob.
+//
+// Decoding errors stop the stream and are reported by Err.
+//
+// NOTE: There is no such thing in the Java standard library.
+func ReadGob[T any](r io.Reader) Stream[T] {
+	dec := gob.NewDecoder(r)
+	return newIteratorStream(func() (T, bool, error) {
+		var el T
+		if err := dec.Decode(&el); err != nil {
+			if err == io.EOF {
+				return el, false, nil
+			}
+			return el, false, err
+		}
+		return el, true, nil
+	})
+}
+
+// WriteFrames writes the elements of the stream to w, each one encoded by encode and prefixed by its length
+// as a 4-byte big-endian integer. The elements can be read back with ReadFrames.
+//
+// It is a terminal operation. It returns the first encoding or write error, or the error of the stream.
+// An element encoded to more than DefaultMaxFrameSize bytes is an ErrFrameTooLarge error, and nothing is written for it.
+//
+// NOTE: There is no such thing in the Java standard library.
+func WriteFrames[T any](w io.Writer, stream Stream[T], encode func(T) ([]byte, error)) error {
+	return WriteFramesWithOptions(w, stream, encode, FramesOptions{})
+}
+
+// WriteFramesWithOptions is like WriteFrames, but it writes the frames according to the given options.
+func WriteFramesWithOptions[T any](w io.Writer, stream Stream[T], encode func(T) ([]byte, error), opts FramesOptions) error {
+	maxSize := opts.maxFrameSize()
+	var index int64
+	return forEachUntilErr(stream, func(t T) error {
+		data, err := encode(t)
+		if err != nil {
+			return err
+		}
+		if uint64(len(data)) > maxSize {
+			return fmt.Errorf("%w: frame %d has %d bytes", ErrFrameTooLarge, index, len(data))
+		}
+		index++
+		var header [4]byte
+		binary.BigEndian.PutUint32(header[:], uint32(len(data)))
+		if _, err := w.Write(header[:]); err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+		if opts.CRC {
+			var checksum [4]byte
+			binary.BigEndian.PutUint32(checksum[:], crc32.Checksum(data, crcTable))
+			_, err = w.Write(checksum[:])
+		}
+		return err
+	})
+}
+
+// ReadFrames returns a lazy stream of the elements written to r by WriteFrames, each one decoded by decode.
+//
+// Read and decoding errors stop the stream and are reported by Err.
+// A truncated frame is reported as io.ErrUnexpectedEOF.
+//
+// NOTE: There is no such thing in the Java standard library.
+func ReadFrames[T any](r io.Reader, decode func([]byte) (T, error)) Stream[T] {
+	return ReadFramesWithOptions(r, decode, FramesOptions{})
+}
+
+// ReadFramesWithOptions is like ReadFrames, but it reads the frames according to the given options.
+func ReadFramesWithOptions[T any](r io.Reader, decode func([]byte) (T, error), opts FramesOptions) Stream[T] {
+	maxSize := opts.maxFrameSize()
+	var index int64
+	return newIteratorStream(func() (T, bool, error) {
+		var zero T
+		var header [4]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			if err == io.EOF {
+				return zero, false, nil
+			}
+			return zero, false, err
+		}
+		size := binary.BigEndian.Uint32(header[:])
+		if uint64(size) > maxSize {
+			return zero, false, fmt.Errorf("%w: frame %d has %d bytes", ErrFrameTooLarge, index, size)
+		}
+
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return zero, false, noEOF(err)
+		}
+		if opts.CRC {
+			var checksum [4]byte
+			if _, err := io.ReadFull(r, checksum[:]); err != nil {
+				return zero, false, noEOF(err)
+			}
+			if binary.BigEndian.Uint32(checksum[:]) != crc32.Checksum(data, crcTable) {
+				return zero, false, fmt.Errorf("%w: frame %d", ErrFrameChecksum, index)
+			}
+		}
+
+		el, err := decode(data)
+		if err != nil {
+			return zero, false, fmt.Errorf("stream: decoding frame %d: %w", index, err)
+		}
+		index++
+		return el, true, nil
+	})
+}
+
+// noEOF converts io.EOF into io.ErrUnexpectedEOF.
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,90 @@
+package stream_test
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+type checkpoint struct {
+	Key   string
+	Count int
+}
+
+func TestGob(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, stream.WriteGob(&buf, stream.Of(checkpoint{"a", 1}, checkpoint{"b", 2}, checkpoint{"c", 3})))
+
+	s := stream.ReadGob[checkpoint](&buf).Filter(func(c checkpoint) bool { return c.Count > 1 })
+
+	require.Equal(t, []checkpoint{{"b", 2}, {"c", 3}}, s.ToArray())
+	require.NoError(t, s.Err())
+
+	t.Run("corrupted input", func(t *testing.T) {
+		s := stream.ReadGob[checkpoint](bytes.NewReader([]byte{0x07, 0xff, 0x01}))
+
+		require.Nil(t, s.ToArray())
+		require.Error(t, s.Err())
+	})
+}
+
+func TestFrames(t *testing.T) {
+	encode := func(i int) ([]byte, error) { return []byte(strconv.Itoa(i)), nil }
+	decode := func(b []byte) (int, error) { return strconv.Atoi(string(b)) }
+
+	t.Run("round trip", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, stream.WriteFrames(&buf, stream.Of(1, 22, 333), encode))
+		require.Equal(t, []byte{0, 0, 0, 1, '1', 0, 0, 0, 2, '2', '2', 0, 0, 0, 3, '3', '3', '3'}, buf.Bytes())
+
+		require.Equal(t, []int{1, 22, 333}, stream.ReadFrames(&buf, decode).ToArray())
+	})
+
+	t.Run("CRC", func(t *testing.T) {
+		opts := stream.FramesOptions{CRC: true}
+		var buf bytes.Buffer
+		require.NoError(t, stream.WriteFramesWithOptions(&buf, stream.Of(1, 22), encode, opts))
+
+		data := buf.Bytes()
+		require.Equal(t, []int{1, 22}, stream.ReadFramesWithOptions(bytes.NewReader(data), decode, opts).ToArray())
+
+		data[len(data)-5] = '3'
+		s := stream.ReadFramesWithOptions(bytes.NewReader(data), decode, opts)
+		require.Nil(t, s.ToArray())
+		require.ErrorIs(t, s.Err(), stream.ErrFrameChecksum)
+	})
+
+	t.Run("truncated frame", func(t *testing.T) {
+		s := stream.ReadFrames(bytes.NewReader([]byte{0, 0, 0, 2, '2'}), decode)
+
+		require.Nil(t, s.ToArray())
+		require.ErrorIs(t, s.Err(), io.ErrUnexpectedEOF)
+	})
+
+	t.Run("frame too large", func(t *testing.T) {
+		s := stream.ReadFramesWithOptions(bytes.NewReader([]byte{0, 0, 1, 0}), decode, stream.FramesOptions{MaxFrameSize: 16})
+
+		require.Nil(t, s.ToArray())
+		require.ErrorIs(t, s.Err(), stream.ErrFrameTooLarge)
+	})
+
+	t.Run("writing a frame too large", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := stream.WriteFramesWithOptions(&buf, stream.Of(1, 22, 333), encode, stream.FramesOptions{MaxFrameSize: 2})
+
+		require.ErrorIs(t, err, stream.ErrFrameTooLarge)
+		require.EqualError(t, err, "stream: frame too large: frame 2 has 3 bytes")
+		require.Equal(t, []int{1, 22}, stream.ReadFrames(&buf, decode).ToArray())
+	})
+
+	t.Run("decode error", func(t *testing.T) {
+		s := stream.ReadFrames(bytes.NewReader([]byte{0, 0, 0, 1, 'x'}), decode)
+
+		require.Nil(t, s.ToArray())
+		require.ErrorIs(t, s.Err(), strconv.ErrSyntax)
+	})
+}
@@ -167,6 +167,9 @@ type Stream[T any] interface {
 
 	// OnClose returns an equivalent stream with an additional close handler.
 	//
+	// The close handlers are shared by all the streams derived from the same stream,
+	// and run once, when any of them is closed.
+	//
 	// 	java: S onClose(Runnable closeHandler)
 	OnClose(closeHandler func()) Stream[T]
 
@@ -189,4 +192,25 @@ type Stream[T any] interface {
 	//
 	// 	java: S unordered()
 	Unordered() Stream[T]
+
+	// Methods that are not part of the Java API:
+
+	// Recover returns an equivalent stream in which panics in the user-provided functions are recovered.
+	//
+	// When a function panics, the operation stops, the close handlers of the stream are called,
+	// the terminal operation returns the zero value of its result
+	// and Err returns a *CallbackPanicError describing the panic.
+	//
+	// The functions passed to the intermediate operations of a lazy stream are called by the terminal operation,
+	// but SliceStream calls them eagerly, when the intermediate operation is called.
+	// Either way, the panic is reported by the terminal operation of the stream that was built by the panicking operation.
+	// The elements of a lazy stream can only be consumed once, so Recover and a panic apply to its whole pipeline.
+	// A SliceStream can be reused instead, so only the returned stream and the streams derived from it are affected:
+	// Recover does not change the stream it is called on, and a panic does not fail the streams it was derived from.
+	Recover() Stream[T]
+
+	// Err returns the error that caused this stream to stop, if any.
+	//
+	// Like bufio.Scanner.Err, it should be checked after the terminal operation has been executed.
+	Err() error
 }
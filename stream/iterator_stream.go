@@ -0,0 +1,653 @@
+package stream
+
+import (
+	"fmt"
+	"sort"
+)
+
+// compile-time interface check
+var _ Stream[int] = (*IteratorStream[int])(nil)
+
+// IteratorStream is a lazy Stream implementation.
+//
+// Its elements are pulled one by one from the source when a terminal operation is executed,
+// which makes it suitable for infinite streams and for sources that do not fit in memory (files, network connections, etc.).
+// Unlike SliceStream, an IteratorStream can be consumed only once.
+type IteratorStream[T any] struct {
+	pull  func() (T, bool)
+	state *pipelineState
+	// unsorted and sortBy are set on the stages returned by SortedWithComparator,
+	// so that Limit can keep only the elements it needs instead of sorting all of them.
+	unsorted *IteratorStream[T]
+	sortBy   func(T, T) int
+}
+
+// pipelineState holds the state shared by all the stages of a lazy stream pipeline.
+type pipelineState struct {
+	closeHandlers []func()
+	closed        bool
+	recover       bool
+	err           error
+}
+
+// fail records err as the error of the pipeline, unless there already is one.
+func (p *pipelineState) fail(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+// newIteratorStream returns a stream whose elements are produced by next.
+//
+// next returns false when there are no more elements.
+// If it returns an error the stream stops and the error is reported by Err.
+func newIteratorStream[T any](next func() (T, bool, error)) *IteratorStream[T] {
+	return newIteratorStreamOn(&pipelineState{}, next)
+}
+
+// newIteratorStreamOn is like newIteratorStream, but the stream is a stage of the pipeline with the given state.
+func newIteratorStreamOn[T any](state *pipelineState, next func() (T, bool, error)) *IteratorStream[T] {
+	done := false
+	return &IteratorStream[T]{
+		state: state,
+		pull: func() (T, bool) {
+			var zero T
+			if done || state.err != nil {
+				return zero, false
+			}
+			el, ok, err := next()
+			if err != nil {
+				state.fail(err)
+			}
+			if err != nil || !ok {
+				done = true
+				return zero, false
+			}
+			return el, true
+		},
+	}
+}
+
+// sliceSource returns a source function for newIteratorStream that yields the given elements.
+func sliceSource[T any](elements []T) func() (T, bool, error) {
+	i := 0
+	return func() (T, bool, error) {
+		if i >= len(elements) {
+			var zero T
+			return zero, false, nil
+		}
+		i++
+		return elements[i-1], true, nil
+	}
+}
+
+// deriveIteratorStream returns a new stage of the pipeline of s whose elements are produced by pull.
+func deriveIteratorStream[T any, R any](s *IteratorStream[T], pull func() (R, bool)) *IteratorStream[R] {
+	return &IteratorStream[R]{pull: pull, state: s.state}
+}
+
+// call calls f, recovering a panic inside of it if the stream is in Recover mode.
+// It returns false if f panicked.
+func (s *IteratorStream[T]) call(index int64, f func()) bool {
+	if err := catch(s.state.recover, index, f); err != nil {
+		s.state.fail(err)
+		return false
+	}
+	return true
+}
+
+// each calls f for each element of this stream until f returns false.
+func (s *IteratorStream[T]) each(f func(T) bool) {
+	var i int64
+	for el, ok := s.pull(); ok; el, ok = s.pull() {
+		next := true
+		if !s.call(i, func() { next = f(el) }) || !next {
+			return
+		}
+		i++
+	}
+}
+
+// failed reports whether the stream has failed.
+// A failed stream is closed, so that its close handlers are guaranteed to run.
+func (s *IteratorStream[T]) failed() bool {
+	if s.state.err == nil {
+		return false
+	}
+	s.Close()
+	return true
+}
+
+// AllMatch returns whether all elements of this stream match the provided predicate.
+//
+//	java: boolean allMatch(Predicate<? super T> predicate)
+func (s *IteratorStream[T]) AllMatch(predicate func(T) bool) bool {
+	res := true
+	s.each(func(el T) bool {
+		res = predicate(el)
+		return res
+	})
+	if s.failed() {
+		return false
+	}
+	return res
+}
+
+// AnyMatch returns whether any elements of this stream match the provided predicate.
+//
+//	java: boolean anyMatch(Predicate<? super T> predicate)
+func (s *IteratorStream[T]) AnyMatch(predicate func(T) bool) bool {
+	res := false
+	s.each(func(el T) bool {
+		res = predicate(el)
+		return !res
+	})
+	if s.failed() {
+		return false
+	}
+	return res
+}
+
+// NoneMatch returns whether no elements of this stream match the provided predicate.
+//
+//	java: boolean noneMatch(Predicate<? super T> predicate)
+func (s *IteratorStream[T]) NoneMatch(predicate func(T) bool) bool {
+	res := true
+	s.each(func(el T) bool {
+		res = !predicate(el)
+		return res
+	})
+	if s.failed() {
+		return false
+	}
+	return res
+}
+
+// Count returns the count of elements in this stream.
+//
+//	java: long count()
+func (s *IteratorStream[T]) Count() int64 {
+	var count int64
+	for _, ok := s.pull(); ok; _, ok = s.pull() {
+		count++
+	}
+	if s.failed() {
+		return 0
+	}
+	return count
+}
+
+// Distinct returns a stream consisting of the distinct elements (according to the "==" operator) of this stream.
+//
+// NOTE: In Java all objects can be compared via Objects.equals.
+// In Go, that is not the case, and not everything can be compared via ==.
+// In order for this method to be able to be implemented the constraint of the generic type should be "comparable", not "any".
+//
+//	java: Stream<T> distinct()
+func (s *IteratorStream[T]) Distinct() Stream[T] {
+	panic(`stream: Distinct cannot be called on a Stream containted by "any"`)
+}
+
+// Filter returns a stream consisting of the elements of this stream that match the given predicate.
+//
+//	java: Stream<T> filter(Predicate<? super T> predicate)
+func (s *IteratorStream[T]) Filter(predicate func(T) bool) Stream[T] {
+	var i int64
+	return deriveIteratorStream(s, func() (T, bool) {
+		for el, ok := s.pull(); ok; el, ok = s.pull() {
+			keep := false
+			if !s.call(i, func() { keep = predicate(el) }) {
+				break
+			}
+			i++
+			if keep {
+				return el, true
+			}
+		}
+		var zero T
+		return zero, false
+	})
+}
+
+// FindAny returns a pointer describing some element of the stream, or a nil pointer if the stream is empty.
+//
+//	java: Optional<T> findAny()
+func (s *IteratorStream[T]) FindAny() *T {
+	return s.FindFirst()
+}
+
+// FindFirst returns a pointer describing the first element of this stream, or a nil pointer if the stream is empty.
+//
+//	java: Optional<T> findFirst()
+func (s *IteratorStream[T]) FindFirst() *T {
+	el, ok := s.pull()
+	if s.failed() || !ok {
+		return nil
+	}
+	return &el
+}
+
+// FlatMapToInt returns an Stream[int] consisting of the results of replacing each element
+// of this stream with the contents of a mapped stream produced by applying the provided mapping
+// function to each element.
+//
+//	java: IntStream flatMapToInt(Function<? super T,? extends IntStream> mapper)
+//	java: LongStream flatMapToLong(Function<? super T,? extends LongStream> mapper)
+func (s *IteratorStream[T]) FlatMapToInt(mapper func(T) Stream[int64]) Stream[int64] {
+	return FlatMap[T](s, mapper)
+}
+
+// FlatMapToDouble returns an Stream[float64] consisting of the results of replacing each element
+// of this stream with the contents of a mapped stream produced by applying the provided mapping
+// function to each element.
+//
+//	java: DoubleStream flatMapToDouble(Function<? super T,? extends DoubleStream> mapper)
+func (s *IteratorStream[T]) FlatMapToDouble(mapper func(T) Stream[float64]) Stream[float64] {
+	return FlatMap[T](s, mapper)
+}
+
+// ForEach performs an action for each element of this stream.
+//
+//	java: void forEach(Consumer<? super T> action)
+func (s *IteratorStream[T]) ForEach(consumer func(T)) {
+	s.each(func(el T) bool {
+		consumer(el)
+		return true
+	})
+	s.failed()
+}
+
+// ForEachOrdered performs an action for each element of this stream, in the encounter order of the stream if the stream has a defined encounter order.
+//
+//	java: void forEachOrdered(Consumer<? super T> action)
+func (s *IteratorStream[T]) ForEachOrdered(consumer func(T)) {
+	s.ForEach(consumer)
+}
+
+// Limit returns a stream consisting of the elements of this stream, truncated to be no longer than maxSize in length.
+//
+// It panics if maxSize is negative.
+//
+//	java: Stream<T> limit(long maxSize)
+func (s *IteratorStream[T]) Limit(maxSize int64) Stream[T] {
+	if maxSize < 0 {
+		panic(fmt.Sprintf("stream: invalid maxSize %d", maxSize))
+	}
+	if s.sortBy != nil {
+		return s.unsorted.bottomK(maxSize, s.sortBy)
+	}
+
+	var taken int64
+	return deriveIteratorStream(s, func() (T, bool) {
+		if taken >= maxSize {
+			var zero T
+			return zero, false
+		}
+		taken++
+		return s.pull()
+	})
+}
+
+// MapToInt returns an Stream[int64] consisting of the results of applying the given function to the elements of this stream.
+//
+//	java: IntStream mapToInt(ToIntFunction<? super T> mapper)
+//	java: LongStream mapToLong(ToLongFunction<? super T> mapper)
+func (s *IteratorStream[T]) MapToInt(mapper func(T) int64) Stream[int64] {
+	return Map[T](s, mapper)
+}
+
+// MapToDouble returns a DoubleStream consisting of the results of applying the given function to the elements of this stream.
+//
+//	java: DoubleStream mapToDouble(ToDoubleFunction<? super T> mapper)
+func (s *IteratorStream[T]) MapToDouble(mapper func(T) float64) Stream[float64] {
+	return Map[T](s, mapper)
+}
+
+// Max returns the maximum element of this stream according to the provided comparator.
+//
+//	java: Optional<T> max(Comparator<? super T> comparator)
+func (s *IteratorStream[T]) Max(comparator func(T, T) int) *T {
+	return s.Reduce(func(max, t T) T {
+		if comparator(t, max) > 0 {
+			return t
+		}
+		return max
+	})
+}
+
+// Min returns the minimum element of this stream according to the provided comparator.
+//
+//	java: Optional<T> min(Comparator<? super T> comparator)
+func (s *IteratorStream[T]) Min(comparator func(T, T) int) *T {
+	return s.Reduce(func(min, t T) T {
+		if comparator(t, min) < 0 {
+			return t
+		}
+		return min
+	})
+}
+
+// Peek returns a stream consisting of the elements of this stream, additionally performing the provided action on each element as elements are consumed from the resulting stream.
+//
+//	java: Stream<T> peek(Consumer<? super T> action)
+func (s *IteratorStream[T]) Peek(action func(T)) Stream[T] {
+	var i int64
+	return deriveIteratorStream(s, func() (T, bool) {
+		el, ok := s.pull()
+		if !ok || !s.call(i, func() { action(el) }) {
+			var zero T
+			return zero, false
+		}
+		i++
+		return el, true
+	})
+}
+
+// Reduce performs a reduction on the elements of this stream, using an associative accumulation function, and returns an Optional describing the reduced value, if any.
+//
+//	java: Optional<T> reduce(BinaryOperator<T> accumulator)
+func (s *IteratorStream[T]) Reduce(accumulator func(T, T) T) *T {
+	var res T
+	first := true
+	s.each(func(el T) bool {
+		if first {
+			res, first = el, false
+			return true
+		}
+		res = accumulator(res, el)
+		return true
+	})
+	if s.failed() || first {
+		return nil
+	}
+	return &res
+}
+
+// ReduceWithIdentity performs a reduction on the elements of this stream, using the provided identity value and an associative accumulation function, and returns the reduced value.
+//
+// NOTE: In Java this method overloads the "reduce" method, but Go does not support method overloads, so we need to change the name.
+//
+//	java: T reduce(T identity, BinaryOperator<T> accumulator)
+func (s *IteratorStream[T]) ReduceWithIdentity(identity T, accumulator func(T, T) T) T {
+	result := identity
+	s.each(func(t T) bool {
+		result = accumulator(result, t)
+		return true
+	})
+	if s.failed() {
+		var zero T
+		return zero
+	}
+	return result
+}
+
+// Skip returns a stream consisting of the remaining elements of this stream after discarding the first n elements of the stream.
+//
+// java: Stream<T> skip(long n)
+func (s *IteratorStream[T]) Skip(n int64) Stream[T] {
+	skipped := false
+	return deriveIteratorStream(s, func() (T, bool) {
+		if !skipped {
+			skipped = true
+			for i := int64(0); i < n; i++ {
+				if _, ok := s.pull(); !ok {
+					break
+				}
+			}
+		}
+		return s.pull()
+	})
+}
+
+// Sorted returns a stream consisting of the elements of this stream, sorted according to natural order.
+//
+// NOTE: This method will not be able to implemented in Go, because Go does not have an interface that support "<" and ">".
+// Even comparable supports only ==.
+// The ony way to implement this would be define a custom interface that support these checks and use it as a constraint.
+//
+//	java: Stream<T> sorted()
+func (s *IteratorStream[T]) Sorted() Stream[T] {
+	panic("stream: Sorted can only be implemented on types that have defined their ways of being sorted. Use SortedWithComparator.")
+}
+
+// Sorted returns a stream consisting of the elements of this stream, sorted according to the provided Comparator.
+//
+// NOTE: This is a stateful operation - all the elements of this stream are consumed
+// and sorted when the first element of the resulting stream is requested.
+// If the next operation is Limit, only the elements that are kept are sorted, as in BottomK.
+//
+//	java: Stream<T> sorted(Comparator<? super T> comparator)
+func (s *IteratorStream[T]) SortedWithComparator(comparator func(T, T) int) Stream[T] {
+	var next func() (T, bool, error)
+	sorted := deriveIteratorStream(s, func() (T, bool) {
+		if next == nil {
+			sortable := sortable[T]{comparator: comparator}
+			for el, ok := s.pull(); ok; el, ok = s.pull() {
+				sortable.data = append(sortable.data, el)
+			}
+			if s.state.err != nil || !s.call(-1, func() { sort.Sort(sortable) }) {
+				sortable.data = nil
+			}
+			next = sliceSource(sortable.data)
+		}
+		el, ok, _ := next()
+		return el, ok
+	})
+	sorted.unsorted = s
+	sorted.sortBy = comparator
+	return sorted
+}
+
+// bottomK returns a stream of the k smallest elements of this stream according to comparator, in ascending order.
+// Like SortedWithComparator, the elements are consumed when the first element of the resulting stream is requested.
+func (s *IteratorStream[T]) bottomK(k int64, comparator func(T, T) int) Stream[T] {
+	var next func() (T, bool, error)
+	return deriveIteratorStream(s, func() (T, bool) {
+		if next == nil {
+			var smallest []T
+			if k > 0 {
+				h := newBoundedHeap(int(k), comparator)
+				s.each(func(el T) bool {
+					h.add(el)
+					return true
+				})
+				if s.state.err == nil {
+					smallest = h.sorted()
+				}
+			}
+			next = sliceSource(smallest)
+		}
+		el, ok, _ := next()
+		return el, ok
+	})
+}
+
+// ToArray returns an array containing the elements of this stream.
+//
+//	java: Object[] toArray()
+//	java: <A> A[] toArray(IntFunction<A[]> generator)
+func (s *IteratorStream[T]) ToArray() []T {
+	res := []T{}
+	for el, ok := s.pull(); ok; el, ok = s.pull() {
+		res = append(res, el)
+	}
+	if s.failed() {
+		return nil
+	}
+	return res
+}
+
+// Methods inherited from BaseStream:
+
+// Close closes this stream, causing all close handlers for this stream pipeline to be called.
+//
+//	java: void close()
+func (s *IteratorStream[T]) Close() {
+	if s.state.closed {
+		return
+	}
+	s.state.closed = true
+	for _, closeHandler := range s.state.closeHandlers {
+		closeHandler()
+	}
+}
+
+// IsParallel returns whether this stream, if a terminal operation were to be executed, would execute in parallel.
+//
+// IteratorStream is always sequential, so this function will always return false.
+//
+//	java: boolean isParallel()
+func (s *IteratorStream[T]) IsParallel() bool {
+	return false
+}
+
+// Iterator returns an iterator for the elements of this stream.
+//
+//	java: Iterator<T> iterator()
+func (s *IteratorStream[T]) Iterator() Iterator[T] {
+	return &pullIterator[T]{pull: s.pull}
+}
+
+// OnClose returns an equivalent stream with an additional close handler.
+//
+//	java: S onClose(Runnable closeHandler)
+func (s *IteratorStream[T]) OnClose(closeHandler func()) Stream[T] {
+	s.state.closeHandlers = append(s.state.closeHandlers, closeHandler)
+	return s
+}
+
+// Parallel returns an equivalent stream that is parallel.
+//
+// IteratorStream is always sequential, so this function will always
+// return the same stream without doing anything.
+//
+//	java: S parallel()
+func (s *IteratorStream[T]) Parallel() Stream[T] {
+	return s
+}
+
+// Sequential returns an equivalent stream that is sequential.
+//
+// IteratorStream is always sequential, so this function will always
+// return the same stream without doing anything.
+//
+//	java: S sequential()
+func (s *IteratorStream[T]) Sequential() Stream[T] {
+	return s
+}
+
+// Spliterator returns a spliterator for the elements of this stream.
+//
+//	java: Spliterator<T> spliterator()
+func (s *IteratorStream[T]) Spliterator() Spliterator[T] {
+	return &pullSpliterator[T]{pull: s.pull}
+}
+
+// Unordered returns an equivalent stream that is unordered.
+//
+// IteratorStream is always sequential and ordered, so this function will always
+// return the same stream without doing anything.
+//
+//	java: S unordered()
+func (s *IteratorStream[T]) Unordered() Stream[T] {
+	return s
+}
+
+// Recover returns an equivalent stream in which panics in the user-provided functions are recovered.
+//
+// When a function panics, the operation stops, the close handlers of the stream are called,
+// the terminal operation returns the zero value of its result
+// and Err returns a *CallbackPanicError describing the panic.
+func (s *IteratorStream[T]) Recover() Stream[T] {
+	s.state.recover = true
+	return s
+}
+
+// Err returns the error that caused this stream to stop, if any.
+//
+// Like bufio.Scanner.Err, it should be checked after the terminal operation has been executed.
+func (s *IteratorStream[T]) Err() error {
+	return s.state.err
+}
+
+// pullIterator is an Iterator that pulls its elements from a function.
+type pullIterator[T any] struct {
+	pull    func() (T, bool)
+	next    T
+	hasNext bool
+	fetched bool
+}
+
+func (it *pullIterator[T]) HasNext() bool {
+	if !it.fetched {
+		it.next, it.hasNext = it.pull()
+		it.fetched = true
+	}
+	return it.hasNext
+}
+
+func (it *pullIterator[T]) Next() T {
+	if !it.HasNext() {
+		panic("stream: no more elements in iterator")
+	}
+	it.fetched = false
+	return it.next
+}
+
+// pullSpliterator is a Spliterator that pulls its elements from a function.
+// It cannot be split, because the elements of an IteratorStream can only be pulled sequentially.
+type pullSpliterator[T any] struct {
+	pull func() (T, bool)
+}
+
+// TryAdvance performs the given action on the next element, if there is one, and reports whether there was.
+//
+//	java: boolean tryAdvance(Consumer<? super T> action)
+func (sp *pullSpliterator[T]) TryAdvance(action func(T)) bool {
+	el, ok := sp.pull()
+	if ok {
+		action(el)
+	}
+	return ok
+}
+
+// ForEachRemaining performs the given action on each remaining element.
+//
+//	java: void forEachRemaining(Consumer<? super T> action)
+func (sp *pullSpliterator[T]) ForEachRemaining(action func(T)) {
+	for sp.TryAdvance(action) {
+	}
+}
+
+// TrySplit always returns nil, since the elements cannot be partitioned.
+//
+//	java: Spliterator<T> trySplit()
+func (sp *pullSpliterator[T]) TrySplit() Spliterator[T] {
+	return nil
+}
+
+// lazy returns an IteratorStream with the elements, the Recover mode and the error of s, which closes s when it is closed,
+// so that the intermediate operations that cannot be methods of Stream can be implemented once, lazily.
+func lazy[T any](s Stream[T]) *IteratorStream[T] {
+	switch s := s.(type) {
+	case *IteratorStream[T]:
+		return s
+	case *SliceStream[T]:
+		l := newIteratorStream(sliceSource(s.items()))
+		l.state.closeHandlers = []func(){s.Close}
+		l.state.recover = s.recover
+		l.state.err = s.err
+		return l
+	}
+
+	it := s.Iterator()
+	l := newIteratorStream(func() (T, bool, error) {
+		if it.HasNext() {
+			return it.Next(), true, nil
+		}
+		var zero T
+		return zero, false, s.Err()
+	})
+	l.state.closeHandlers = []func(){s.Close}
+	return l
+}
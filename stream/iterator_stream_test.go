@@ -0,0 +1,149 @@
+package stream
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIteratorStream(t *testing.T) {
+	of := func(elements ...int) *IteratorStream[int] {
+		return newIteratorStream(sliceSource(elements))
+	}
+	sum := func(i1, i2 int) int { return i1 + i2 }
+
+	t.Run("TestMatch", func(t *testing.T) {
+		require.False(t, of(1, 2, 3).AllMatch(func(i int) bool { return i == 1 }))
+		require.True(t, of(1, 1).AllMatch(func(i int) bool { return i == 1 }))
+		require.True(t, of(1, 2, 3).AnyMatch(func(i int) bool { return i == 3 }))
+		require.False(t, of(1, 2, 3).AnyMatch(func(i int) bool { return i == 4 }))
+		require.True(t, of(1, 2, 3).NoneMatch(func(i int) bool { return i == 4 }))
+	})
+
+	t.Run("TestCount", func(t *testing.T) {
+		require.Equal(t, int64(3), of(1, 2, 3).Count())
+		require.Equal(t, int64(0), of().Count())
+	})
+
+	t.Run("TestFilter", func(t *testing.T) {
+		filtered := of(1, 2, 3).Filter(func(i int) bool { return i%2 != 0 })
+		require.Equal(t, []int{1, 3}, filtered.ToArray())
+	})
+
+	t.Run("TestFindFirst", func(t *testing.T) {
+		res := of(1, 2, 3).FindFirst()
+		require.NotNil(t, res)
+		require.Equal(t, 1, *res)
+
+		require.Nil(t, of().FindAny())
+	})
+
+	t.Run("TestLimitAndSkip", func(t *testing.T) {
+		require.Equal(t, []int{1, 2}, of(1, 2, 3).Limit(2).ToArray())
+		require.Equal(t, []int{1, 2, 3}, of(1, 2, 3).Limit(4).ToArray())
+		require.Equal(t, []int{3}, of(1, 2, 3).Skip(2).ToArray())
+		require.Equal(t, []int{}, of(1, 2, 3).Skip(5).ToArray())
+	})
+
+	t.Run("TestLimitIsLazy", func(t *testing.T) {
+		var pulled int
+		s := newIteratorStream(func() (int, bool, error) {
+			pulled++
+			return pulled, true, nil
+		})
+		require.Equal(t, []int{1, 2, 3}, s.Limit(3).ToArray())
+		require.Equal(t, 3, pulled)
+	})
+
+	t.Run("TestMap", func(t *testing.T) {
+		mapped := Map[int](of(1, 2, 3), func(i int) int { return i * 2 })
+		require.Equal(t, []int{2, 4, 6}, mapped.ToArray())
+	})
+
+	t.Run("TestFlatMap", func(t *testing.T) {
+		mapped := of(1, 2).FlatMapToInt(func(i int) Stream[int64] {
+			return newSliceStream(int64(i), int64(i*10))
+		})
+		require.Equal(t, []int64{1, 10, 2, 20}, mapped.ToArray())
+	})
+
+	t.Run("TestFlatMapClosesOpenInnerStream", func(t *testing.T) {
+		var closed int
+		mapped := FlatMap[int](of(1, 2), func(i int) Stream[int] {
+			return of(i, i).OnClose(func() { closed++ })
+		}).Limit(1)
+
+		require.Equal(t, []int{1}, mapped.ToArray())
+		mapped.Close()
+		require.Equal(t, 1, closed)
+	})
+
+	t.Run("TestMaxMin", func(t *testing.T) {
+		require.Equal(t, 3, *of(2, 3, 1).Max(compareIntFunc))
+		require.Equal(t, 1, *of(2, 3, 1).Min(compareIntFunc))
+		require.Nil(t, of().Max(compareIntFunc))
+	})
+
+	t.Run("TestPeek", func(t *testing.T) {
+		res := []int{}
+		of(1, 2, 3).Peek(func(i int) { res = append(res, i) }).ForEach(func(int) {})
+		require.Equal(t, []int{1, 2, 3}, res)
+	})
+
+	t.Run("TestReduce", func(t *testing.T) {
+		require.Equal(t, 6, *of(1, 2, 3).Reduce(sum))
+		require.Nil(t, of().Reduce(sum))
+		require.Equal(t, 6, of(1, 2, 3).ReduceWithIdentity(0, sum))
+	})
+
+	t.Run("TestSortedWithComparator", func(t *testing.T) {
+		require.Equal(t, []int{1, 2, 3}, of(2, 3, 1).SortedWithComparator(compareIntFunc).ToArray())
+	})
+
+	t.Run("TestIterator", func(t *testing.T) {
+		it := of(1, 2).Iterator()
+		require.True(t, it.HasNext())
+		require.True(t, it.HasNext())
+		require.Equal(t, 1, it.Next())
+		require.Equal(t, 2, it.Next())
+		require.False(t, it.HasNext())
+		require.Panics(t, func() { it.Next() })
+	})
+
+	t.Run("TestSpliterator", func(t *testing.T) {
+		sp := of(1, 2, 3).Spliterator()
+		var res []int
+		require.True(t, sp.TryAdvance(func(i int) { res = append(res, i) }))
+		sp.ForEachRemaining(func(i int) { res = append(res, i) })
+		require.False(t, sp.TryAdvance(func(i int) { res = append(res, i) }))
+		require.Equal(t, []int{1, 2, 3}, res)
+		require.Nil(t, sp.TrySplit())
+	})
+
+	t.Run("TestSourceError", func(t *testing.T) {
+		var closed bool
+		s := newIteratorStream(func() (int, bool, error) {
+			return 0, false, errors.New("read failed")
+		})
+		filtered := s.OnClose(func() { closed = true }).Filter(func(int) bool { return true })
+
+		require.Equal(t, int64(0), filtered.Count())
+		require.EqualError(t, filtered.Err(), "read failed")
+		require.True(t, closed)
+	})
+
+	t.Run("TestRecover", func(t *testing.T) {
+		s := of(1, 2, 3).Recover().Filter(func(i int) bool {
+			if i == 3 {
+				panic("boom")
+			}
+			return true
+		})
+
+		require.Nil(t, s.ToArray())
+		var panicErr *CallbackPanicError
+		require.ErrorAs(t, s.Err(), &panicErr)
+		require.Equal(t, int64(2), panicErr.Index)
+	})
+}
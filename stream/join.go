@@ -0,0 +1,264 @@
+package stream
+
+// JoinOptions configures the join operations.
+//
+// The zero value makes a hash join: the right stream is read into memory, indexed by key,
+// and the left stream is streamed against it.
+type JoinOptions[K any] struct {
+	// SortedBy, if not nil, is the order of the keys that both streams are sorted by.
+	// The join is then a sort-merge join, which walks both streams at the same time
+	// and only keeps in memory the right elements with the current key.
+	// Unmatched right elements of FullOuterJoin are then emitted in key order, instead of at the end.
+	SortedBy func(K, K) int
+}
+
+// InnerJoin returns a lazy stream of the pairs of the elements of left and right with equal keys.
+// The pairs are in the order of the left elements, and for each of them, in the order of the right elements.
+// Closing the stream closes both left and right, and the errors of both are reported by Err.
+// The stream is in Recover mode if either left or right is, and then a panic of leftKey, rightKey
+// or the SortedBy comparator is reported by Err.
+//
+// NOTE: There is no such thing in the Java standard library.
+func InnerJoin[L any, R any, K comparable](left Stream[L], right Stream[R], leftKey func(L) K, rightKey func(R) K) Stream[Pair[L, R]] {
+	return InnerJoinWithOptions(left, right, leftKey, rightKey, JoinOptions[K]{})
+}
+
+// InnerJoinWithOptions is like InnerJoin, but with the given options.
+func InnerJoinWithOptions[L any, R any, K comparable](left Stream[L], right Stream[R], leftKey func(L) K, rightKey func(R) K, opts JoinOptions[K]) Stream[Pair[L, R]] {
+	return join(left, right, leftKey, rightKey, opts, func(step joinStep[L, R]) []Pair[L, R] {
+		if step.left == nil {
+			return nil
+		}
+		pairs := make([]Pair[L, R], len(step.rights))
+		for i, r := range step.rights {
+			pairs[i] = Pair[L, R]{First: *step.left, Second: r}
+		}
+		return pairs
+	})
+}
+
+// LeftJoin is like InnerJoin, but the left elements without a matching right element are paired with nil.
+//
+// NOTE: There is no such thing in the Java standard library.
+func LeftJoin[L any, R any, K comparable](left Stream[L], right Stream[R], leftKey func(L) K, rightKey func(R) K) Stream[Pair[L, *R]] {
+	return LeftJoinWithOptions(left, right, leftKey, rightKey, JoinOptions[K]{})
+}
+
+// LeftJoinWithOptions is like LeftJoin, but with the given options.
+func LeftJoinWithOptions[L any, R any, K comparable](left Stream[L], right Stream[R], leftKey func(L) K, rightKey func(R) K, opts JoinOptions[K]) Stream[Pair[L, *R]] {
+	return join(left, right, leftKey, rightKey, opts, func(step joinStep[L, R]) []Pair[L, *R] {
+		if step.left == nil {
+			return nil
+		}
+		if len(step.rights) == 0 {
+			return []Pair[L, *R]{{First: *step.left}}
+		}
+		pairs := make([]Pair[L, *R], len(step.rights))
+		for i, r := range step.rights {
+			r := r
+			pairs[i] = Pair[L, *R]{First: *step.left, Second: &r}
+		}
+		return pairs
+	})
+}
+
+// FullOuterJoin is like LeftJoin, but the right elements without a matching left element are also returned, paired with nil.
+// They are returned after all the left elements, unless the join is a sort-merge join.
+//
+// NOTE: There is no such thing in the Java standard library.
+func FullOuterJoin[L any, R any, K comparable](left Stream[L], right Stream[R], leftKey func(L) K, rightKey func(R) K) Stream[Pair[*L, *R]] {
+	return FullOuterJoinWithOptions(left, right, leftKey, rightKey, JoinOptions[K]{})
+}
+
+// FullOuterJoinWithOptions is like FullOuterJoin, but with the given options.
+func FullOuterJoinWithOptions[L any, R any, K comparable](left Stream[L], right Stream[R], leftKey func(L) K, rightKey func(R) K, opts JoinOptions[K]) Stream[Pair[*L, *R]] {
+	return join(left, right, leftKey, rightKey, opts, func(step joinStep[L, R]) []Pair[*L, *R] {
+		if step.left != nil && len(step.rights) == 0 {
+			return []Pair[*L, *R]{{First: step.left}}
+		}
+		pairs := make([]Pair[*L, *R], len(step.rights))
+		for i, r := range step.rights {
+			r := r
+			pairs[i] = Pair[*L, *R]{Second: &r}
+			if step.left != nil {
+				l := *step.left
+				pairs[i].First = &l
+			}
+		}
+		return pairs
+	})
+}
+
+// SemiJoin returns a lazy stream of the elements of left that have a matching element in right.
+// It is closed as InnerJoin.
+//
+// NOTE: There is no such thing in the Java standard library.
+func SemiJoin[L any, R any, K comparable](left Stream[L], right Stream[R], leftKey func(L) K, rightKey func(R) K) Stream[L] {
+	return SemiJoinWithOptions(left, right, leftKey, rightKey, JoinOptions[K]{})
+}
+
+// SemiJoinWithOptions is like SemiJoin, but with the given options.
+func SemiJoinWithOptions[L any, R any, K comparable](left Stream[L], right Stream[R], leftKey func(L) K, rightKey func(R) K, opts JoinOptions[K]) Stream[L] {
+	return join(left, right, leftKey, rightKey, opts, func(step joinStep[L, R]) []L {
+		if step.left == nil || len(step.rights) == 0 {
+			return nil
+		}
+		return []L{*step.left}
+	})
+}
+
+// AntiJoin returns a lazy stream of the elements of left that do not have a matching element in right.
+// It is closed as InnerJoin.
+//
+// NOTE: There is no such thing in the Java standard library.
+func AntiJoin[L any, R any, K comparable](left Stream[L], right Stream[R], leftKey func(L) K, rightKey func(R) K) Stream[L] {
+	return AntiJoinWithOptions(left, right, leftKey, rightKey, JoinOptions[K]{})
+}
+
+// AntiJoinWithOptions is like AntiJoin, but with the given options.
+func AntiJoinWithOptions[L any, R any, K comparable](left Stream[L], right Stream[R], leftKey func(L) K, rightKey func(R) K, opts JoinOptions[K]) Stream[L] {
+	return join(left, right, leftKey, rightKey, opts, func(step joinStep[L, R]) []L {
+		if step.left == nil || len(step.rights) != 0 {
+			return nil
+		}
+		return []L{*step.left}
+	})
+}
+
+// joinStep is a step of a join: a left element with its matching right elements,
+// or, if left is nil, right elements that did not match any left element.
+type joinStep[L any, R any] struct {
+	left   *L
+	rights []R
+}
+
+// join returns a lazy stream of the elements produced by emit from the steps of the join of left and right.
+func join[L any, R any, K comparable, O any](left Stream[L], right Stream[R], leftKey func(L) K, rightKey func(R) K, opts JoinOptions[K], emit func(joinStep[L, R]) []O) Stream[O] {
+	next := hashJoin(leftKey, rightKey)
+	if opts.SortedBy != nil {
+		next = sortMergeJoin(leftKey, rightKey, opts.SortedBy)
+	}
+
+	var queue []O
+	return zipped(left, right, func(la *IteratorStream[L], lb *IteratorStream[R]) (O, bool) {
+		for len(queue) == 0 {
+			step, ok := next(la, lb)
+			if !ok || la.state.err != nil || lb.state.err != nil {
+				var zero O
+				return zero, false
+			}
+			queue = emit(step)
+		}
+		o := queue[0]
+		queue = queue[1:]
+		return o, true
+	})
+}
+
+// hashJoin returns the function that produces the steps of a hash join.
+func hashJoin[L any, R any, K comparable](leftKey func(L) K, rightKey func(R) K) func(*IteratorStream[L], *IteratorStream[R]) (joinStep[L, R], bool) {
+	var (
+		built   bool
+		done    bool
+		rights  []R
+		matched []bool
+		index   = map[K][]int{}
+	)
+	return func(la *IteratorStream[L], lb *IteratorStream[R]) (joinStep[L, R], bool) {
+		if !built {
+			built = true
+			for r, ok := lb.pull(); ok; r, ok = lb.pull() {
+				k := rightKey(r)
+				index[k] = append(index[k], len(rights))
+				rights = append(rights, r)
+			}
+			matched = make([]bool, len(rights))
+		}
+
+		if l, ok := la.pull(); ok {
+			indexes := index[leftKey(l)]
+			matches := make([]R, len(indexes))
+			for i, j := range indexes {
+				matches[i] = rights[j]
+				matched[j] = true
+			}
+			return joinStep[L, R]{left: &l, rights: matches}, true
+		}
+
+		if done {
+			return joinStep[L, R]{}, false
+		}
+		done = true
+		var unmatched []R
+		for i, r := range rights {
+			if !matched[i] {
+				unmatched = append(unmatched, r)
+			}
+		}
+		return joinStep[L, R]{rights: unmatched}, len(unmatched) > 0
+	}
+}
+
+// sortMergeJoin returns the function that produces the steps of a sort-merge join of streams sorted by compare.
+func sortMergeJoin[L any, R any, K any](leftKey func(L) K, rightKey func(R) K, compare func(K, K) int) func(*IteratorStream[L], *IteratorStream[R]) (joinStep[L, R], bool) {
+	var (
+		started  bool
+		head     R
+		hasHead  bool
+		group    []R
+		groupKey K
+		matched  bool
+		pending  *L
+	)
+	return func(la *IteratorStream[L], lb *IteratorStream[R]) (joinStep[L, R], bool) {
+		if !started {
+			started = true
+			head, hasHead = lb.pull()
+		}
+		if pending == nil {
+			if l, ok := la.pull(); ok {
+				pending = &l
+			}
+		}
+
+		// the right elements before the key of the pending left element, or all of them if there is none
+		var unmatched []R
+		var lk K
+		if pending != nil {
+			lk = leftKey(*pending)
+			if group != nil && compare(groupKey, lk) == 0 {
+				matched = true
+				l := pending
+				pending = nil
+				return joinStep[L, R]{left: l, rights: group}, true
+			}
+		}
+		if group != nil && !matched {
+			unmatched = group
+		}
+		group = nil
+		for hasHead && (pending == nil || compare(rightKey(head), lk) < 0) {
+			unmatched = append(unmatched, head)
+			head, hasHead = lb.pull()
+		}
+		if pending != nil {
+			for hasHead && compare(rightKey(head), lk) == 0 {
+				groupKey = rightKey(head)
+				group = append(group, head)
+				head, hasHead = lb.pull()
+			}
+			matched = len(group) > 0
+		}
+
+		if len(unmatched) > 0 {
+			matched = false
+			return joinStep[L, R]{rights: unmatched}, true
+		}
+		if pending == nil {
+			return joinStep[L, R]{}, false
+		}
+		l := pending
+		pending = nil
+		return joinStep[L, R]{left: l, rights: group}, true
+	}
+}
@@ -0,0 +1,165 @@
+package stream_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+type customer struct {
+	ID   int
+	Name string
+}
+
+type order struct {
+	ID         int
+	CustomerID int
+}
+
+func customerID(c customer) int   { return c.ID }
+func orderCustomerID(o order) int { return o.CustomerID }
+
+func joinStrategies() map[string]stream.JoinOptions[int] {
+	return map[string]stream.JoinOptions[int]{
+		"hash":       {},
+		"sort-merge": {SortedBy: compareInts},
+	}
+}
+
+func orders() stream.Stream[order] {
+	return stream.Of(order{10, 1}, order{11, 1}, order{12, 3}, order{13, 5})
+}
+
+func customers() stream.Stream[customer] {
+	return stream.Of(customer{1, "ada"}, customer{2, "alan"}, customer{3, "grace"}, customer{4, "linus"})
+}
+
+func TestInnerJoin(t *testing.T) {
+	for name, opts := range joinStrategies() {
+		opts := opts
+		t.Run(name, func(t *testing.T) {
+			s := stream.InnerJoinWithOptions(orders(), customers(), orderCustomerID, customerID, opts)
+
+			require.Equal(t, []stream.Pair[order, customer]{
+				{order{10, 1}, customer{1, "ada"}},
+				{order{11, 1}, customer{1, "ada"}},
+				{order{12, 3}, customer{3, "grace"}},
+			}, s.ToArray())
+		})
+	}
+
+	t.Run("many to many", func(t *testing.T) {
+		s := stream.InnerJoin(stream.Of("a1", "b1", "a2"), stream.Of("ax", "ay"), firstByte, firstByte)
+
+		require.Equal(t, []stream.Pair[string, string]{{"a1", "ax"}, {"a1", "ay"}, {"a2", "ax"}, {"a2", "ay"}}, s.ToArray())
+	})
+
+	t.Run("sort-merge is lazy", func(t *testing.T) {
+		lines := stream.Lines(strings.NewReader("1\n2\n3\n4\n"))
+		s := stream.InnerJoinWithOptions(stream.Of(1, 2, 3), lines, func(i int) byte { return byte('0' + i) }, firstByte, stream.JoinOptions[byte]{
+			SortedBy: func(a, b byte) int { return int(a) - int(b) },
+		})
+
+		require.Equal(t, stream.Pair[int, string]{1, "1"}, *s.FindFirst())
+	})
+}
+
+func firstByte(s string) byte { return s[0] }
+
+func TestLeftJoin(t *testing.T) {
+	for name, opts := range joinStrategies() {
+		opts := opts
+		t.Run(name, func(t *testing.T) {
+			pairs := stream.LeftJoinWithOptions(orders(), customers(), orderCustomerID, customerID, opts).ToArray()
+
+			require.Len(t, pairs, 4)
+			require.Equal(t, "ada", pairs[1].Second.Name)
+			require.Equal(t, "grace", pairs[2].Second.Name)
+			require.Equal(t, order{13, 5}, pairs[3].First)
+			require.Nil(t, pairs[3].Second)
+		})
+	}
+}
+
+func TestFullOuterJoin(t *testing.T) {
+	describe := func(p stream.Pair[*order, *customer]) string {
+		var sb strings.Builder
+		if p.First != nil {
+			sb.WriteString(strconv.Itoa(p.First.ID))
+		}
+		sb.WriteString("-")
+		if p.Second != nil {
+			sb.WriteString(p.Second.Name)
+		}
+		return sb.String()
+	}
+
+	t.Run("hash", func(t *testing.T) {
+		s := stream.FullOuterJoin(orders(), customers(), orderCustomerID, customerID)
+
+		require.Equal(t, []string{"10-ada", "11-ada", "12-grace", "13-", "-alan", "-linus"}, stream.Map(s, describe).ToArray())
+	})
+
+	t.Run("sort-merge", func(t *testing.T) {
+		s := stream.FullOuterJoinWithOptions(orders(), customers(), orderCustomerID, customerID, stream.JoinOptions[int]{SortedBy: compareInts})
+
+		require.Equal(t, []string{"10-ada", "11-ada", "-alan", "12-grace", "-linus", "13-"}, stream.Map(s, describe).ToArray())
+	})
+}
+
+func TestSemiAndAntiJoin(t *testing.T) {
+	for name, opts := range joinStrategies() {
+		opts := opts
+		t.Run(name, func(t *testing.T) {
+			withOrders := stream.SemiJoinWithOptions(customers(), orders(), customerID, orderCustomerID, opts)
+			require.Equal(t, []customer{{1, "ada"}, {3, "grace"}}, withOrders.ToArray())
+
+			withoutOrders := stream.AntiJoinWithOptions(customers(), orders(), customerID, orderCustomerID, opts)
+			require.Equal(t, []customer{{2, "alan"}, {4, "linus"}}, withoutOrders.ToArray())
+		})
+	}
+}
+
+func TestJoinClose(t *testing.T) {
+	closed := 0
+	s := stream.InnerJoin(
+		orders().OnClose(func() { closed++ }),
+		customers().OnClose(func() { closed++ }),
+		orderCustomerID, customerID,
+	)
+	s.Close()
+
+	require.Equal(t, 2, closed)
+}
+
+func TestJoinRecover(t *testing.T) {
+	for name, opts := range joinStrategies() {
+		opts := opts
+		t.Run(name, func(t *testing.T) {
+			key := func(o order) int {
+				boomOn(o.ID, 12)
+				return o.CustomerID
+			}
+			s := stream.InnerJoinWithOptions(orders().Recover(), customers(), key, customerID, opts)
+
+			require.Nil(t, s.ToArray())
+			requireBoom(t, s.Err())
+		})
+	}
+
+	t.Run("sort-merge comparator", func(t *testing.T) {
+		s := stream.InnerJoinWithOptions(orders(), customers().Recover(), orderCustomerID, customerID, stream.JoinOptions[int]{
+			SortedBy: func(a, b int) int {
+				boomOn(a, 3)
+				boomOn(b, 3)
+				return compareInts(a, b)
+			},
+		})
+
+		require.Nil(t, s.ToArray())
+		requireBoom(t, s.Err())
+	})
+}
@@ -0,0 +1,130 @@
+package stream
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// JSONEncodeOptions configures the output of EncodeJSONWithOptions.
+type JSONEncodeOptions struct {
+	// Array makes the elements be written as a single JSON array instead of newline-delimited JSON.
+	Array bool
+}
+
+// DecodeJSON returns a lazy stream of the JSON values read from r, decoded into T.
+//
+// The input can either be newline-delimited JSON (one value per line, or any sequence of whitespace-separated values)
+// or a single top-level JSON array, whose elements are decoded one by one, without reading the whole document.
+// The format is detected by looking at the first non-whitespace character of the input,
+// so newline-delimited JSON whose first value is an array is not supported.
+//
+// Decoding errors stop the stream and are reported by Err.
+//
+// NOTE: There is no such thing in the Java standard library.
+func DecodeJSON[T any](r io.Reader) Stream[T] {
+	br := bufio.NewReader(r)
+	dec := json.NewDecoder(br)
+	var (
+		started bool
+		array   bool
+		index   int64
+	)
+
+	return newIteratorStream(func() (T, bool, error) {
+		var el T
+		if !started {
+			started = true
+			first, err := peekNonSpace(br)
+			if err == io.EOF {
+				return el, false, nil
+			}
+			if err != nil {
+				return el, false, err
+			}
+			if first == '[' {
+				array = true
+				if _, err := dec.Token(); err != nil {
+					return el, false, err
+				}
+			}
+		}
+
+		if array && !dec.More() {
+			if _, err := dec.Token(); err != nil {
+				return el, false, fmt.Errorf("stream: decoding JSON array: %w", err)
+			}
+			return el, false, nil
+		}
+		if err := dec.Decode(&el); err != nil {
+			if err == io.EOF && !array {
+				return el, false, nil
+			}
+			return el, false, fmt.Errorf("stream: decoding JSON element %d: %w", index, err)
+		}
+		index++
+		return el, true, nil
+	})
+}
+
+// peekNonSpace returns the first non-whitespace byte of r, without consuming it.
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b, r.UnreadByte()
+	}
+}
+
+// EncodeJSON writes the elements of the stream to w as newline-delimited JSON, one element at a time.
+//
+// It is a terminal operation. It returns the first encoding or write error, or the error of the stream.
+//
+// NOTE: There is no such thing in the Java standard library.
+func EncodeJSON[T any](w io.Writer, stream Stream[T]) error {
+	return EncodeJSONWithOptions(w, stream, JSONEncodeOptions{})
+}
+
+// EncodeJSONWithOptions writes the elements of the stream to w as JSON, one element at a time,
+// according to the given options.
+//
+// It is a terminal operation. It returns the first encoding or write error, or the error of the stream.
+func EncodeJSONWithOptions[T any](w io.Writer, stream Stream[T], opts JSONEncodeOptions) error {
+	if !opts.Array {
+		enc := json.NewEncoder(w)
+		return forEachUntilErr(stream, func(t T) error {
+			return enc.Encode(t)
+		})
+	}
+
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	first := true
+	err := forEachUntilErr(stream, func(t T) error {
+		data, err := json.Marshal(t)
+		if err != nil {
+			return err
+		}
+		if !first {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		first = false
+		_, err = w.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "]\n")
+	return err
+}
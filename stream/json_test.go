@@ -0,0 +1,112 @@
+package stream_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+type event struct {
+	ID   int    `json:"id"`
+	Kind string `json:"kind"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	expected := []event{{ID: 1, Kind: "click"}, {ID: 2, Kind: "view"}}
+
+	t.Run("NDJSON", func(t *testing.T) {
+		s := stream.DecodeJSON[event](strings.NewReader(`{"id":1,"kind":"click"}
+{"id":2,"kind":"view"}
+`))
+
+		require.Equal(t, expected, s.ToArray())
+		require.NoError(t, s.Err())
+	})
+
+	t.Run("array", func(t *testing.T) {
+		s := stream.DecodeJSON[event](strings.NewReader(`
+		[
+			{"id": 1, "kind": "click"},
+			{"id": 2, "kind": "view"}
+		]`))
+
+		require.Equal(t, expected, s.ToArray())
+		require.NoError(t, s.Err())
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		require.Equal(t, []event{}, stream.DecodeJSON[event](strings.NewReader("  \n")).ToArray())
+		require.Equal(t, []event{}, stream.DecodeJSON[event](strings.NewReader("[]")).ToArray())
+	})
+
+	t.Run("lazy", func(t *testing.T) {
+		s := stream.DecodeJSON[event](strings.NewReader(`[{"id":1,"kind":"click"}, this is not JSON`))
+
+		require.Equal(t, expected[0], *s.FindFirst())
+		require.NoError(t, s.Err())
+	})
+
+	t.Run("invalid element", func(t *testing.T) {
+		s := stream.DecodeJSON[event](strings.NewReader(`{"id":1,"kind":"click"}
+{"id":"two"}
+`))
+
+		require.Equal(t, int64(0), s.Count())
+		var typeErr *json.UnmarshalTypeError
+		require.ErrorAs(t, s.Err(), &typeErr)
+		require.Contains(t, s.Err().Error(), "element 1")
+	})
+
+	t.Run("truncated array", func(t *testing.T) {
+		s := stream.DecodeJSON[event](strings.NewReader(`[{"id":1,"kind":"click"}`))
+
+		require.Nil(t, s.ToArray())
+		require.Error(t, s.Err())
+	})
+}
+
+func TestEncodeJSON(t *testing.T) {
+	s := stream.Of(event{ID: 1, Kind: "click"}, event{ID: 2, Kind: "view"})
+
+	t.Run("NDJSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, stream.EncodeJSON(&buf, s))
+
+		require.Equal(t, "{\"id\":1,\"kind\":\"click\"}\n{\"id\":2,\"kind\":\"view\"}\n", buf.String())
+	})
+
+	t.Run("array", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, stream.EncodeJSONWithOptions(&buf, s, stream.JSONEncodeOptions{Array: true}))
+
+		require.Equal(t, "[{\"id\":1,\"kind\":\"click\"},{\"id\":2,\"kind\":\"view\"}]\n", buf.String())
+
+		buf.Reset()
+		require.NoError(t, stream.EncodeJSONWithOptions(&buf, stream.Empty[event](), stream.JSONEncodeOptions{Array: true}))
+		require.Equal(t, "[]\n", buf.String())
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, stream.EncodeJSONWithOptions(&buf, s, stream.JSONEncodeOptions{Array: true}))
+
+		require.Equal(t, s.ToArray(), stream.DecodeJSON[event](&buf).ToArray())
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		var closed bool
+		err := stream.EncodeJSON(failingWriter{}, s.OnClose(func() { closed = true }))
+
+		require.EqualError(t, err, "write failed")
+		require.True(t, closed)
+	})
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("write failed") }
@@ -0,0 +1,114 @@
+package stream
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LinesOptions configures the streams returned by LinesWithOptions and FileLinesWithOptions.
+type LinesOptions struct {
+	// MaxLineLength is the maximum length of a line in bytes.
+	// A longer line stops the stream with bufio.ErrTooLong.
+	// If it is 0, bufio.MaxScanTokenSize is used.
+	MaxLineLength int
+
+	// Split is the function used to split the input into elements.
+	// It can be used to stream words (bufio.ScanWords), runes (bufio.ScanRunes),
+	// NUL-delimited records, etc.
+	// If it is nil, bufio.ScanLines is used.
+	Split bufio.SplitFunc
+}
+
+// Lines returns a lazy stream of the lines read from r.
+// The line terminators ("\n" or "\r\n") are not included in the elements.
+//
+// Read errors stop the stream and are reported by Err.
+//
+//	java: Stream<String> lines() (BufferedReader)
+func Lines(r io.Reader) Stream[string] {
+	return LinesWithOptions(r, LinesOptions{})
+}
+
+// LinesWithOptions returns a lazy stream of the elements read from r,
+// split according to the given options.
+//
+// NOTE: In Java the only way to read anything but lines is to use a Scanner directly.
+func LinesWithOptions(r io.Reader, opts LinesOptions) Stream[string] {
+	scanner := bufio.NewScanner(r)
+	if opts.MaxLineLength > 0 {
+		scanner.Buffer(make([]byte, 0, minInt(opts.MaxLineLength, 4096)), opts.MaxLineLength)
+	}
+	if opts.Split != nil {
+		scanner.Split(opts.Split)
+	}
+
+	return newIteratorStream(func() (string, bool, error) {
+		if scanner.Scan() {
+			return scanner.Text(), true, nil
+		}
+		return "", false, scanner.Err()
+	})
+}
+
+// FileLines opens the file at path and returns a lazy stream of its lines.
+// Files with a ".gz" extension are transparently decompressed.
+//
+// The file is closed by the close handler of the stream, so the stream should be closed once it is no longer needed.
+//
+//	java: static Stream<String> lines(Path path) (Files)
+func FileLines(path string) (Stream[string], error) {
+	return FileLinesWithOptions(path, LinesOptions{})
+}
+
+// FileLinesWithOptions opens the file at path and returns a lazy stream of its elements,
+// split according to the given options.
+// Files with a ".gz" extension are transparently decompressed.
+//
+// The file is closed by the close handler of the stream, so the stream should be closed once it is no longer needed.
+func FileLinesWithOptions(path string, opts LinesOptions) (Stream[string], error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var r io.Reader = f
+	closeHandler := func() { _ = f.Close() }
+	if filepath.Ext(path) == ".gz" {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		r = gz
+		closeHandler = func() {
+			_ = gz.Close()
+			_ = f.Close()
+		}
+	}
+
+	return LinesWithOptions(r, opts).OnClose(closeHandler), nil
+}
+
+// ScanNUL is a bufio.SplitFunc that splits the input on NUL bytes,
+// like the output of "find -print0" or "git ls-files -z".
+func ScanNUL(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	for i, b := range data {
+		if b == 0 {
+			return i + 1, data[:i], nil
+		}
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,79 @@
+package stream_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLines(t *testing.T) {
+	s := stream.Lines(strings.NewReader("first\r\nsecond\n\nthird"))
+
+	require.Equal(t, []string{"first", "second", "", "third"}, s.ToArray())
+	require.NoError(t, s.Err())
+}
+
+func TestLinesWithOptions(t *testing.T) {
+	t.Run("words", func(t *testing.T) {
+		s := stream.LinesWithOptions(strings.NewReader("one two\nthree"), stream.LinesOptions{Split: bufio.ScanWords})
+
+		require.Equal(t, []string{"one", "two", "three"}, s.ToArray())
+	})
+
+	t.Run("NUL-delimited", func(t *testing.T) {
+		s := stream.LinesWithOptions(strings.NewReader("a.go\x00b c.go\x00d.go"), stream.LinesOptions{Split: stream.ScanNUL})
+
+		require.Equal(t, []string{"a.go", "b c.go", "d.go"}, s.ToArray())
+	})
+
+	t.Run("max line length", func(t *testing.T) {
+		s := stream.LinesWithOptions(strings.NewReader("short\n"+strings.Repeat("x", 100)), stream.LinesOptions{MaxLineLength: 10})
+
+		require.Nil(t, s.ToArray())
+		require.ErrorIs(t, s.Err(), bufio.ErrTooLong)
+	})
+}
+
+func TestFileLines(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("plain file", func(t *testing.T) {
+		path := filepath.Join(dir, "app.log")
+		require.NoError(t, os.WriteFile(path, []byte("INFO start\nERROR failed\nINFO stop\n"), 0o600))
+
+		s, err := stream.FileLines(path)
+		require.NoError(t, err)
+		defer s.Close()
+
+		errors := s.Filter(func(line string) bool { return strings.HasPrefix(line, "ERROR") }).ToArray()
+		require.Equal(t, []string{"ERROR failed"}, errors)
+	})
+
+	t.Run("gzip file", func(t *testing.T) {
+		path := filepath.Join(dir, "app.log.gz")
+		f, err := os.Create(path)
+		require.NoError(t, err)
+		gz := gzip.NewWriter(f)
+		_, err = gz.Write([]byte("one\ntwo\n"))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+		require.NoError(t, f.Close())
+
+		s, err := stream.FileLines(path)
+		require.NoError(t, err)
+		defer s.Close()
+
+		require.Equal(t, []string{"one", "two"}, s.ToArray())
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := stream.FileLines(filepath.Join(dir, "missing.log"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
@@ -0,0 +1,109 @@
+package stream
+
+import "container/heap"
+
+// MergeOptions configures MergeSortedWithOptions.
+type MergeOptions struct {
+	// Dedup drops the elements that are equal (according to the comparator) to the previous element of the merged stream.
+	Dedup bool
+}
+
+// MergeSorted returns a lazy stream of the elements of the given streams, each one sorted according to comparator,
+// merged into a single sorted stream.
+// Equal elements are in the order of the streams they come from.
+//
+// Only the next element of each stream is kept in memory, in a heap, so merging k streams takes O(log k) per element.
+// Closing the stream closes all the given streams, and the error of any of them is reported by Err.
+// The stream is in Recover mode if any of the given streams is, and then a panic of comparator is reported by Err.
+//
+// NOTE: There is no such thing in the Java standard library.
+func MergeSorted[T any](comparator func(T, T) int, streams ...Stream[T]) Stream[T] {
+	return MergeSortedWithOptions(comparator, MergeOptions{}, streams...)
+}
+
+// MergeSortedWithOptions is like MergeSorted, but with the given options.
+func MergeSortedWithOptions[T any](comparator func(T, T) int, opts MergeOptions, streams ...Stream[T]) Stream[T] {
+	sources := make([]*IteratorStream[T], len(streams))
+	for i, s := range streams {
+		sources[i] = lazy(s)
+	}
+
+	h := &mergeHeap[T]{comparator: comparator}
+	started := false
+	var (
+		last    T
+		emitted bool
+	)
+	// pull pushes the next element of the source with the given index to the heap, if there is one.
+	pull := func(src int) error {
+		if el, ok := sources[src].pull(); ok {
+			heap.Push(h, mergeItem[T]{el: el, src: src})
+		}
+		return sources[src].state.err
+	}
+
+	// next returns the next element of the merged stream.
+	next := func() (T, bool, error) {
+		var zero T
+		if !started {
+			started = true
+			for i := range sources {
+				if err := pull(i); err != nil {
+					return zero, false, err
+				}
+			}
+		}
+
+		for h.Len() > 0 {
+			item := heap.Pop(h).(mergeItem[T])
+			if err := pull(item.src); err != nil {
+				return zero, false, err
+			}
+			if opts.Dedup && emitted && comparator(last, item.el) == 0 {
+				continue
+			}
+			last, emitted = item.el, true
+			return item.el, true, nil
+		}
+		return zero, false, nil
+	}
+
+	state := &pipelineState{}
+	for _, src := range sources {
+		state.closeHandlers = append(state.closeHandlers, src.Close)
+		state.recover = state.recover || src.state.recover
+	}
+	return newIteratorStreamOn(state, func() (el T, ok bool, err error) {
+		if panicErr := catch(state.recover, -1, func() { el, ok, err = next() }); panicErr != nil {
+			return el, false, panicErr
+		}
+		return el, ok, err
+	})
+}
+
+// mergeItem is an element of the heap of MergeSorted, with the index of the stream it comes from.
+type mergeItem[T any] struct {
+	el  T
+	src int
+}
+
+// mergeHeap is a heap.Interface of the next elements of the streams of MergeSorted.
+type mergeHeap[T any] struct {
+	items      []mergeItem[T]
+	comparator func(T, T) int
+}
+
+func (h *mergeHeap[T]) Len() int { return len(h.items) }
+func (h *mergeHeap[T]) Less(i, j int) bool {
+	if c := h.comparator(h.items[i].el, h.items[j].el); c != 0 {
+		return c < 0
+	}
+	return h.items[i].src < h.items[j].src
+}
+func (h *mergeHeap[T]) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *mergeHeap[T]) Push(x any)    { h.items = append(h.items, x.(mergeItem[T])) }
+func (h *mergeHeap[T]) Pop() any {
+	item := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return item
+}
@@ -0,0 +1,74 @@
+package stream_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+func compareInts(a, b int) int { return a - b }
+
+func TestMergeSorted(t *testing.T) {
+	t.Run("merge", func(t *testing.T) {
+		s := stream.MergeSorted(compareInts, stream.Of(1, 4, 7), stream.Of(2, 5, 8), stream.Of[int](), stream.Of(3, 6, 9, 10))
+
+		require.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, s.ToArray())
+	})
+
+	t.Run("stable", func(t *testing.T) {
+		type line struct {
+			At   int
+			Host string
+		}
+		byTime := func(a, b line) int { return a.At - b.At }
+		s := stream.MergeSorted(byTime,
+			stream.Of(line{1, "b"}, line{2, "b"}),
+			stream.Of(line{1, "a"}, line{2, "a"}),
+		)
+
+		require.Equal(t, []line{{1, "b"}, {1, "a"}, {2, "b"}, {2, "a"}}, s.ToArray())
+	})
+
+	t.Run("dedup", func(t *testing.T) {
+		s := stream.MergeSortedWithOptions(compareInts, stream.MergeOptions{Dedup: true}, stream.Of(1, 2, 2, 3), stream.Of(2, 3, 4))
+
+		require.Equal(t, []int{1, 2, 3, 4}, s.ToArray())
+	})
+
+	t.Run("lazy", func(t *testing.T) {
+		a := stream.Map(stream.Lines(strings.NewReader("1\n3\n5\n")), func(s string) int { return int(s[0] - '0') })
+		b := stream.Map(stream.Lines(strings.NewReader("2\n4\n")), func(s string) int { return int(s[0] - '0') })
+
+		require.Equal(t, []int{1, 2, 3}, stream.MergeSorted(compareInts, a, b).Limit(3).ToArray())
+	})
+
+	t.Run("close and errors", func(t *testing.T) {
+		closed := 0
+		failing := stream.Map(
+			stream.Lines(io.MultiReader(strings.NewReader("1\n"), iotest.ErrReader(errors.New("read failed")))),
+			func(s string) int { return int(s[0] - '0') },
+		).OnClose(func() { closed++ })
+		s := stream.MergeSorted(compareInts, stream.Of(2, 3).OnClose(func() { closed++ }), failing)
+
+		require.Nil(t, s.ToArray())
+		require.EqualError(t, s.Err(), "read failed")
+		require.Equal(t, 2, closed)
+	})
+
+	t.Run("recover", func(t *testing.T) {
+		comparator := func(a, b int) int {
+			boomOn(a, 3)
+			boomOn(b, 3)
+			return compareInts(a, b)
+		}
+		s := stream.MergeSorted(comparator, stream.Of(1, 3), stream.Of(2, 4).Recover())
+
+		require.Nil(t, s.ToArray())
+		require.Equal(t, int64(-1), requireBoom(t, s.Err()).Index)
+	})
+}
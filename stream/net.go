@@ -0,0 +1,90 @@
+package stream
+
+import (
+	"errors"
+	"io"
+	"net"
+)
+
+// maxDatagramSize is the size of the buffer used for reading datagrams, which fits any UDP datagram.
+const maxDatagramSize = 64 << 10
+
+// Datagram is an element of the stream returned by FromPacketConn.
+type Datagram struct {
+	// Data is the payload of the datagram.
+	Data []byte
+	// Addr is the address of the sender.
+	Addr net.Addr
+}
+
+// FromConn returns a lazy stream of the lines read from conn.
+//
+// The connection is closed by the close handler of the stream, which also ends the stream if it is waiting for data.
+// Read errors stop the stream and are reported by Err.
+//
+// NOTE: There is no such thing in the Java standard library.
+func FromConn(conn net.Conn) Stream[string] {
+	return Lines(closedAsEOF{conn}).OnClose(func() { _ = conn.Close() })
+}
+
+// FromConnFrames returns a lazy stream of the elements read from conn as frames written by WriteFrames,
+// each one decoded by decode.
+//
+// The connection is closed by the close handler of the stream, which also ends the stream if it is waiting for data.
+// Read and decoding errors stop the stream and are reported by Err.
+func FromConnFrames[T any](conn net.Conn, decode func([]byte) (T, error)) Stream[T] {
+	return ReadFrames(closedAsEOF{conn}, decode).OnClose(func() { _ = conn.Close() })
+}
+
+// AcceptStream returns a lazy stream of the connections accepted by l.
+//
+// The listener is closed by the close handler of the stream, which also ends the stream if it is waiting for a connection.
+// The accepted connections are not closed by the stream.
+// Accept errors stop the stream and are reported by Err.
+//
+// NOTE: There is no such thing in the Java standard library.
+func AcceptStream(l net.Listener) Stream[net.Conn] {
+	return newIteratorStream(func() (net.Conn, bool, error) {
+		conn, err := l.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil, false, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		return conn, true, nil
+	}).OnClose(func() { _ = l.Close() })
+}
+
+// FromPacketConn returns a lazy stream of the datagrams received by pc.
+//
+// The connection is closed by the close handler of the stream, which also ends the stream if it is waiting for a datagram.
+// Read errors stop the stream and are reported by Err.
+//
+// NOTE: There is no such thing in the Java standard library.
+func FromPacketConn(pc net.PacketConn) Stream[Datagram] {
+	buf := make([]byte, maxDatagramSize)
+	return newIteratorStream(func() (Datagram, bool, error) {
+		n, addr, err := pc.ReadFrom(buf)
+		if errors.Is(err, net.ErrClosed) {
+			return Datagram{}, false, nil
+		}
+		if err != nil {
+			return Datagram{}, false, err
+		}
+		return Datagram{Data: append([]byte{}, buf[:n]...), Addr: addr}, true, nil
+	}).OnClose(func() { _ = pc.Close() })
+}
+
+// closedAsEOF is an io.Reader that reports reads from a closed connection as the end of the input.
+type closedAsEOF struct {
+	r io.Reader
+}
+
+func (r closedAsEOF) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if errors.Is(err, net.ErrClosed) {
+		err = io.EOF
+	}
+	return n, err
+}
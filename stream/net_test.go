@@ -0,0 +1,103 @@
+package stream_test
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromConn(t *testing.T) {
+	client, server := net.Pipe()
+	go func() {
+		_, _ = fmt.Fprint(client, "PING\nGET key\nQUIT\n")
+		_ = client.Close()
+	}()
+
+	s := stream.FromConn(server)
+	defer s.Close()
+
+	require.Equal(t, []string{"PING", "GET key", "QUIT"}, s.ToArray())
+	require.NoError(t, s.Err())
+}
+
+func TestFromConnFrames(t *testing.T) {
+	client, server := net.Pipe()
+	go func() {
+		_ = stream.WriteFrames(client, stream.Of(1, 2, 3), func(i int) ([]byte, error) { return []byte(strconv.Itoa(i)), nil })
+		_ = client.Close()
+	}()
+
+	s := stream.FromConnFrames(server, func(b []byte) (int, error) { return strconv.Atoi(string(b)) })
+
+	require.Equal(t, 6, s.ReduceWithIdentity(0, func(a, b int) int { return a + b }))
+	s.Close()
+}
+
+func TestAcceptStream(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		i := i
+		go func() {
+			conn, err := net.Dial("tcp", l.Addr().String())
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			_, _ = fmt.Fprintf(conn, "hello %d\n", i)
+		}()
+	}
+
+	s := stream.AcceptStream(l)
+	greetings := stream.Map(s.Limit(2), func(conn net.Conn) string {
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		return strings.TrimSpace(line)
+	}).ToArray()
+	require.ElementsMatch(t, []string{"hello 0", "hello 1"}, greetings)
+
+	t.Run("close ends the stream", func(t *testing.T) {
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			s.Close()
+		}()
+
+		require.Nil(t, s.FindFirst())
+		require.NoError(t, s.Err())
+	})
+}
+
+func TestFromPacketConn(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := stream.FromPacketConn(pc)
+	defer s.Close()
+
+	sender, err := net.Dial("udp", pc.LocalAddr().String())
+	require.NoError(t, err)
+	defer sender.Close()
+	for _, metric := range []string{"requests:1|c", "latency:320|ms", "requests:1|c"} {
+		_, err := sender.Write([]byte(metric))
+		require.NoError(t, err)
+	}
+
+	datagrams := s.Limit(3).ToArray()
+	require.Len(t, datagrams, 3)
+	require.Equal(t, "latency:320|ms", string(datagrams[1].Data))
+	require.Equal(t, sender.LocalAddr().String(), datagrams[0].Addr.String())
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		s.Close()
+	}()
+	require.Equal(t, int64(0), s.Count())
+	require.NoError(t, s.Err())
+}
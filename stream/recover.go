@@ -0,0 +1,51 @@
+package stream
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// CallbackPanicError is the error reported by a stream in Recover mode
+// when one of the user-provided functions (predicates, mappers, accumulators, etc.) panics.
+//
+// NOTE: There is no such thing in Java, where an exception thrown by a lambda
+// propagates through the terminal operation to the caller.
+// Go code is not expected to recover from panics coming from other packages,
+// that is why this behaviour is opt-in via Stream.Recover.
+type CallbackPanicError struct {
+	// Index is the index of the element that was being processed when the panic occurred.
+	// It is -1 if the function was not called for a single element (e.g. a comparator).
+	Index int64
+	// Value is the value that was passed to panic.
+	Value any
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack []byte
+}
+
+func (e *CallbackPanicError) Error() string {
+	return fmt.Sprintf("stream: callback panicked on element %d: %v", e.Index, e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, so that errors.Is and errors.As can be used with it.
+func (e *CallbackPanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// catch calls f and, if enabled is true, converts a panic inside of it into a *CallbackPanicError.
+// If enabled is false the panic is not recovered.
+func catch(enabled bool, index int64, f func()) (err error) {
+	if !enabled {
+		f()
+		return nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = &CallbackPanicError{Index: index, Value: r, Stack: debug.Stack()}
+		}
+	}()
+	f()
+	return nil
+}
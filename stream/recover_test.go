@@ -0,0 +1,125 @@
+package stream_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecover(t *testing.T) {
+	panicOnTwo := func(i int) bool {
+		if i == 2 {
+			panic("boom")
+		}
+		return true
+	}
+
+	t.Run("Filter panic is reported by the terminal operation", func(t *testing.T) {
+		var closed int
+		s := stream.Of(1, 2, 3).OnClose(func() { closed++ }).Recover()
+
+		filtered := s.Filter(panicOnTwo)
+		count := filtered.Count()
+
+		require.Equal(t, int64(0), count)
+		require.Equal(t, 1, closed)
+
+		var panicErr *stream.CallbackPanicError
+		require.ErrorAs(t, filtered.Err(), &panicErr)
+		require.Equal(t, int64(1), panicErr.Index)
+		require.Equal(t, "boom", panicErr.Value)
+		require.NotEmpty(t, panicErr.Stack)
+
+		filtered.Close()
+		s.Close()
+		require.Equal(t, 1, closed, "close handlers must run only once per pipeline")
+		require.NoError(t, s.Err())
+	})
+
+	t.Run("branches of a stream fail and recover independently", func(t *testing.T) {
+		base := stream.Of(1, 2, 3)
+
+		failed := base.Filter(func(i int) bool { return i > 1 }).Recover().Filter(panicOnTwo)
+		require.Nil(t, failed.ToArray())
+		require.Error(t, failed.Err())
+
+		require.Equal(t, int64(3), base.Count())
+		require.Equal(t, []int{1, 2, 3}, base.ToArray())
+		require.NoError(t, base.Err())
+		require.Panics(t, func() { base.Filter(panicOnTwo) }, "Recover must not enable recovery for the other branches")
+	})
+
+	t.Run("close handlers run once through lazy operations", func(t *testing.T) {
+		var closed int
+		base := stream.Of(1, 2, 3).OnClose(func() { closed++ })
+		chunks := stream.Chunk(base, 1)
+
+		chunks.Close()
+		base.Close()
+		require.Equal(t, 1, closed)
+	})
+
+	t.Run("Reduce panic", func(t *testing.T) {
+		var closed bool
+		s := stream.Of(1, 2, 3).OnClose(func() { closed = true }).Recover()
+
+		res := s.Reduce(func(i1, i2 int) int {
+			if i2 == 3 {
+				panic(errors.New("bad element"))
+			}
+			return i1 + i2
+		})
+
+		require.Nil(t, res)
+		require.True(t, closed)
+
+		var panicErr *stream.CallbackPanicError
+		require.ErrorAs(t, s.Err(), &panicErr)
+		require.Equal(t, int64(2), panicErr.Index)
+		require.EqualError(t, errors.Unwrap(s.Err()), "bad element")
+	})
+
+	t.Run("Map panic", func(t *testing.T) {
+		s := stream.Of(1, 2, 3).Recover()
+
+		mapped := stream.Map(s, func(i int) int {
+			panicOnTwo(i)
+			return i
+		})
+
+		require.Nil(t, mapped.ToArray())
+		require.Error(t, mapped.Err())
+	})
+
+	t.Run("no panic", func(t *testing.T) {
+		s := stream.Of(1, 2, 3).Recover()
+
+		require.Equal(t, 6, s.ReduceWithIdentity(0, func(i1, i2 int) int { return i1 + i2 }))
+		require.NoError(t, s.Err())
+	})
+
+	t.Run("panics are not recovered by default", func(t *testing.T) {
+		require.Panics(t, func() {
+			stream.Of(1, 2, 3).Filter(panicOnTwo)
+		})
+	})
+}
+
+// boomOn panics with "boom" if el is bad.
+// It is used to make the functions passed to the operations panic on a given element.
+func boomOn[T comparable](el, bad T) {
+	if el == bad {
+		panic("boom")
+	}
+}
+
+// requireBoom asserts that err is the *CallbackPanicError of a panic caused by boomOn, and returns it.
+func requireBoom(t *testing.T, err error) *stream.CallbackPanicError {
+	t.Helper()
+	var panicErr *stream.CallbackPanicError
+	require.ErrorAs(t, err, &panicErr)
+	require.Equal(t, "boom", panicErr.Value)
+	return panicErr
+}
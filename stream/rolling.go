@@ -0,0 +1,157 @@
+package stream
+
+import "fmt"
+
+// RunningSum returns a lazy stream of the sums of the elements of the stream so far.
+//
+// NOTE: There is no such thing in the Java standard library.
+func RunningSum[T Number](stream Stream[T]) Stream[T] {
+	s := lazy(stream)
+	var sum T
+	return deriveIteratorStream(s, func() (T, bool) {
+		el, ok := s.pull()
+		if !ok {
+			return sum, false
+		}
+		sum += el
+		return sum, true
+	})
+}
+
+// MovingAverage returns a lazy stream of the averages of the last window elements of the stream.
+// The first window-1 averages are of the elements so far.
+// If window is 0, the averages are of all the elements so far.
+//
+// It panics if window is negative.
+//
+// NOTE: There is no such thing in the Java standard library.
+func MovingAverage[T Number](stream Stream[T], window int) Stream[float64] {
+	checkRollingWindow(window)
+
+	s := lazy(stream)
+	var (
+		sum   float64
+		ring  []float64
+		next  int
+		count int
+	)
+	return deriveIteratorStream(s, func() (float64, bool) {
+		el, ok := s.pull()
+		if !ok {
+			return 0, false
+		}
+
+		v := float64(el)
+		sum += v
+		switch {
+		case window == 0:
+			count++
+		case count < window:
+			ring = append(ring, v)
+			count++
+		default:
+			sum -= ring[next]
+			ring[next] = v
+			next = (next + 1) % window
+		}
+		return sum / float64(count), true
+	})
+}
+
+// ExponentialSmoothing returns a lazy stream of the exponentially smoothed elements of the stream.
+// The first value is the first element, and every next one is alpha*element + (1-alpha)*previous value.
+//
+// It panics if alpha is not in the interval (0, 1].
+//
+// NOTE: There is no such thing in the Java standard library.
+func ExponentialSmoothing[T Number](stream Stream[T], alpha float64) Stream[float64] {
+	if !(alpha > 0 && alpha <= 1) {
+		panic(fmt.Sprintf("stream: invalid smoothing factor %v", alpha))
+	}
+
+	s := lazy(stream)
+	var (
+		smoothed float64
+		started  bool
+	)
+	return deriveIteratorStream(s, func() (float64, bool) {
+		el, ok := s.pull()
+		if !ok {
+			return 0, false
+		}
+
+		if started {
+			smoothed = alpha*float64(el) + (1-alpha)*smoothed
+		} else {
+			smoothed = float64(el)
+			started = true
+		}
+		return smoothed, true
+	})
+}
+
+// RunningMin returns a lazy stream of the minimums of the last window elements of the stream.
+// The first window-1 minimums are of the elements so far.
+// If window is 0, the minimums are of all the elements so far.
+//
+// It panics if window is negative.
+//
+// NOTE: There is no such thing in the Java standard library.
+func RunningMin[T Ordered](stream Stream[T], window int) Stream[T] {
+	return runningExtreme(stream, window, func(a, b T) bool { return a < b })
+}
+
+// RunningMax returns a lazy stream of the maximums of the last window elements of the stream.
+// The first window-1 maximums are of the elements so far.
+// If window is 0, the maximums are of all the elements so far.
+//
+// It panics if window is negative.
+//
+// NOTE: There is no such thing in the Java standard library.
+func RunningMax[T Ordered](stream Stream[T], window int) Stream[T] {
+	return runningExtreme(stream, window, func(a, b T) bool { return a > b })
+}
+
+// runningExtreme returns a lazy stream of the extremes of the last window elements of the stream,
+// where a is more extreme than b if better(a, b).
+//
+// The candidates are kept in a monotonic deque: each of them is more extreme than the ones after it,
+// so the extreme is the first one, and each element is added and removed at most once.
+func runningExtreme[T any](stream Stream[T], window int, better func(a, b T) bool) Stream[T] {
+	checkRollingWindow(window)
+
+	s := lazy(stream)
+	var (
+		deque []Indexed[T]
+		head  int
+		index int64
+	)
+	return deriveIteratorStream(s, func() (T, bool) {
+		el, ok := s.pull()
+		if !ok {
+			var zero T
+			return zero, false
+		}
+
+		for len(deque) > head && !better(deque[len(deque)-1].Value, el) {
+			deque = deque[:len(deque)-1]
+		}
+		deque = append(deque, Indexed[T]{Index: index, Value: el})
+		if window > 0 && deque[head].Index <= index-int64(window) {
+			head++
+		}
+		if head > len(deque)/2 {
+			deque = append(deque[:0], deque[head:]...)
+			head = 0
+		}
+		index++
+		return deque[head].Value, true
+	})
+}
+
+// checkRollingWindow panics if window is not a valid window size for the rolling aggregates.
+func checkRollingWindow(window int) {
+	if window < 0 {
+		panic(fmt.Sprintf("stream: invalid window size %d", window))
+	}
+}
@@ -0,0 +1,80 @@
+package stream_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunningSum(t *testing.T) {
+	require.Equal(t, []int{1, 3, 6, 10}, stream.RunningSum(stream.Of(1, 2, 3, 4)).ToArray())
+	require.Empty(t, stream.RunningSum(stream.Of[int]()).ToArray())
+}
+
+func TestMovingAverage(t *testing.T) {
+	t.Run("window", func(t *testing.T) {
+		s := stream.MovingAverage(stream.Of(2, 4, 6, 8, 10), 3)
+
+		require.Equal(t, []float64{2, 3, 4, 6, 8}, s.ToArray())
+	})
+
+	t.Run("all elements", func(t *testing.T) {
+		s := stream.MovingAverage(stream.Of(2, 4, 6, 8), 0)
+
+		require.Equal(t, []float64{2, 3, 4, 5}, s.ToArray())
+	})
+
+	t.Run("MapToDouble", func(t *testing.T) {
+		latencies := stream.Of("10ms", "20ms", "60ms").MapToDouble(func(s string) float64 {
+			ms, _ := strconv.ParseFloat(strings.TrimSuffix(s, "ms"), 64)
+			return ms
+		})
+
+		require.Equal(t, []float64{10, 15, 40}, stream.MovingAverage(latencies, 2).ToArray())
+	})
+
+	t.Run("invalid window", func(t *testing.T) {
+		require.Panics(t, func() { stream.MovingAverage(stream.Of(1), -1) })
+	})
+}
+
+func TestExponentialSmoothing(t *testing.T) {
+	s := stream.ExponentialSmoothing(stream.Of(10.0, 20, 20, 0), 0.5)
+	require.Equal(t, []float64{10, 15, 17.5, 8.75}, s.ToArray())
+
+	require.Panics(t, func() { stream.ExponentialSmoothing(stream.Of(1), 0) })
+	require.Panics(t, func() { stream.ExponentialSmoothing(stream.Of(1), 1.5) })
+}
+
+func TestRunningMinMax(t *testing.T) {
+	t.Run("window", func(t *testing.T) {
+		require.Equal(t, []int{5, 3, 3, 1, 1, 1, 4}, stream.RunningMin(stream.Of(5, 3, 4, 1, 6, 7, 4), 3).ToArray())
+		require.Equal(t, []int{5, 5, 5, 4, 6, 7, 7}, stream.RunningMax(stream.Of(5, 3, 4, 1, 6, 7, 4), 3).ToArray())
+	})
+
+	t.Run("all elements", func(t *testing.T) {
+		require.Equal(t, []int{5, 3, 3, 1, 1}, stream.RunningMin(stream.Of(5, 3, 4, 1, 6), 0).ToArray())
+		require.Equal(t, []string{"b", "b", "c"}, stream.RunningMax(stream.Of("b", "a", "c"), 0).ToArray())
+	})
+
+	t.Run("long stream", func(t *testing.T) {
+		elements := make([]int, 1000)
+		for i := range elements {
+			elements[i] = (i * 7919) % 1000
+		}
+		mins := stream.RunningMin(stream.Of(elements...), 10).ToArray()
+
+		for i, min := range mins {
+			expected := elements[i]
+			for j := i; j >= 0 && j > i-10; j-- {
+				if elements[j] < expected {
+					expected = elements[j]
+				}
+			}
+			require.Equal(t, expected, min, "index %d", i)
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package stream
+
+// Union returns a lazy stream of the distinct elements (according to the "==" operator) of a and b:
+// the ones of a in the order they are first seen, followed by the ones of b that are not in a.
+// Closing the stream closes both a and b, and the errors of both are reported by Err.
+// The stream is in Recover mode if either a or b is, and then a panic of the key function of UnionBy is reported by Err.
+//
+// NOTE: There is no such thing in the Java standard library.
+func Union[T comparable](a Stream[T], b Stream[T]) Stream[T] {
+	return UnionBy(a, b, identity[T])
+}
+
+// UnionBy is like Union, but the elements are compared by the keys returned by key.
+// Of the elements with equal keys only the first one is returned.
+func UnionBy[T any, K comparable](a Stream[T], b Stream[T], key func(T) K) Stream[T] {
+	seen := map[K]struct{}{}
+	return zipped(a, b, func(la *IteratorStream[T], lb *IteratorStream[T]) (T, bool) {
+		if el, ok := pullUnseen(la, key, seen); ok {
+			return el, true
+		}
+		return pullUnseen(lb, key, seen)
+	})
+}
+
+// Intersect returns a lazy stream of the distinct elements (according to the "==" operator) of a that are also in b,
+// in the order they are first seen in a.
+// The elements of b are read into memory when the first element is requested.
+// It is closed, and recovers panics of the key function, as Union.
+//
+// NOTE: There is no such thing in the Java standard library.
+func Intersect[T comparable](a Stream[T], b Stream[T]) Stream[T] {
+	return IntersectBy(a, b, identity[T])
+}
+
+// IntersectBy is like Intersect, but the elements are compared by the keys returned by key.
+func IntersectBy[T any, K comparable](a Stream[T], b Stream[T], key func(T) K) Stream[T] {
+	return filterByKeys(a, b, key, true)
+}
+
+// Except returns a lazy stream of the distinct elements (according to the "==" operator) of a that are not in b,
+// in the order they are first seen in a.
+// The elements of b are read into memory when the first element is requested.
+// It is closed, and recovers panics of the key function, as Union.
+//
+// NOTE: There is no such thing in the Java standard library.
+func Except[T comparable](a Stream[T], b Stream[T]) Stream[T] {
+	return ExceptBy(a, b, identity[T])
+}
+
+// ExceptBy is like Except, but the elements are compared by the keys returned by key.
+func ExceptBy[T any, K comparable](a Stream[T], b Stream[T], key func(T) K) Stream[T] {
+	return filterByKeys(a, b, key, false)
+}
+
+// SymmetricDifference returns a lazy stream of the distinct elements (according to the "==" operator)
+// that are in exactly one of a and b: the ones of a in the order they are first seen, followed by the ones of b.
+// The elements of b are read into memory when the first element is requested.
+// It is closed, and recovers panics of the key function, as Union.
+//
+// NOTE: There is no such thing in the Java standard library.
+func SymmetricDifference[T comparable](a Stream[T], b Stream[T]) Stream[T] {
+	return SymmetricDifferenceBy(a, b, identity[T])
+}
+
+// SymmetricDifferenceBy is like SymmetricDifference, but the elements are compared by the keys returned by key.
+func SymmetricDifferenceBy[T any, K comparable](a Stream[T], b Stream[T], key func(T) K) Stream[T] {
+	var (
+		keysOfB  map[K]struct{}
+		elsOfB   []T
+		seen     = map[K]struct{}{}
+		restOfB  *IteratorStream[T]
+		built    bool
+		finished bool
+	)
+	return zipped(a, b, func(la *IteratorStream[T], lb *IteratorStream[T]) (T, bool) {
+		if !built {
+			built = true
+			keysOfB, elsOfB = distinctKeys(lb, key)
+		}
+		if lb.state.err != nil {
+			var zero T
+			return zero, false
+		}
+
+		if !finished {
+			for el, ok := la.pull(); ok; el, ok = la.pull() {
+				k := key(el)
+				if _, ok := seen[k]; ok {
+					continue
+				}
+				seen[k] = struct{}{}
+				if _, ok := keysOfB[k]; !ok {
+					return el, true
+				}
+			}
+			finished = true
+			restOfB = newIteratorStream(sliceSource(elsOfB))
+		}
+
+		return pullUnseen(restOfB, key, seen)
+	})
+}
+
+// identity returns its argument.
+func identity[T any](t T) T {
+	return t
+}
+
+// pullUnseen returns the next element of s whose key is not in seen, and adds its key to seen.
+func pullUnseen[T any, K comparable](s *IteratorStream[T], key func(T) K, seen map[K]struct{}) (T, bool) {
+	for el, ok := s.pull(); ok; el, ok = s.pull() {
+		k := key(el)
+		if _, ok := seen[k]; !ok {
+			seen[k] = struct{}{}
+			return el, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// distinctKeys reads all the elements of s and returns their keys,
+// and the elements with distinct keys in the order they are first seen.
+func distinctKeys[T any, K comparable](s *IteratorStream[T], key func(T) K) (map[K]struct{}, []T) {
+	keys := map[K]struct{}{}
+	var elements []T
+	for el, ok := s.pull(); ok; el, ok = s.pull() {
+		k := key(el)
+		if _, ok := keys[k]; !ok {
+			keys[k] = struct{}{}
+			elements = append(elements, el)
+		}
+	}
+	return keys, elements
+}
+
+// filterByKeys returns a lazy stream of the distinct elements of a whose keys are (if in is true) or are not in b.
+func filterByKeys[T any, K comparable](a Stream[T], b Stream[T], key func(T) K, in bool) Stream[T] {
+	var (
+		keysOfB map[K]struct{}
+		seen    = map[K]struct{}{}
+		built   bool
+	)
+	return zipped(a, b, func(la *IteratorStream[T], lb *IteratorStream[T]) (T, bool) {
+		if !built {
+			built = true
+			keysOfB, _ = distinctKeys(lb, key)
+		}
+		if lb.state.err != nil {
+			var zero T
+			return zero, false
+		}
+		for el, ok := pullUnseen(la, key, seen); ok; el, ok = pullUnseen(la, key, seen) {
+			if _, ok := keysOfB[key(el)]; ok == in {
+				return el, true
+			}
+		}
+		var zero T
+		return zero, false
+	})
+}
@@ -0,0 +1,85 @@
+package stream_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnion(t *testing.T) {
+	s := stream.Union(stream.Of(3, 1, 3, 2), stream.Of(2, 4, 1, 5, 4))
+	require.Equal(t, []int{3, 1, 2, 4, 5}, s.ToArray())
+
+	t.Run("lazy", func(t *testing.T) {
+		s := stream.Union(stream.Lines(strings.NewReader("a\nb\n")), stream.Lines(strings.NewReader("c\n")))
+
+		require.Equal(t, "a", *s.FindFirst())
+	})
+
+	t.Run("by key", func(t *testing.T) {
+		s := stream.UnionBy(stream.Of("Apple", "pear"), stream.Of("apple", "Plum"), strings.ToLower)
+
+		require.Equal(t, []string{"Apple", "pear", "Plum"}, s.ToArray())
+	})
+}
+
+func TestIntersect(t *testing.T) {
+	s := stream.Intersect(stream.Of(3, 1, 3, 2, 5), stream.Of(2, 3, 4))
+	require.Equal(t, []int{3, 2}, s.ToArray())
+
+	t.Run("by key", func(t *testing.T) {
+		type stock struct {
+			SKU string
+			Qty int
+		}
+		warehouse := stream.Of(stock{"A-1", 1}, stock{"B-2", 2})
+		shop := stream.Of(stock{"B-2", 3}, stock{"C-3", 1})
+		sku := func(s stock) string { return s.SKU }
+
+		require.Equal(t, []stock{{"B-2", 2}}, stream.IntersectBy(warehouse, shop, sku).ToArray())
+	})
+}
+
+func TestExcept(t *testing.T) {
+	s := stream.Except(stream.Of(3, 1, 3, 2, 5), stream.Of(2, 3, 4))
+	require.Equal(t, []int{1, 5}, s.ToArray())
+
+	closed := 0
+	s = stream.Except(stream.Of(1).OnClose(func() { closed++ }), stream.Of(2).OnClose(func() { closed++ }))
+	s.Close()
+	require.Equal(t, 2, closed)
+}
+
+func TestSymmetricDifference(t *testing.T) {
+	s := stream.SymmetricDifference(stream.Of(3, 1, 3, 2, 5), stream.Of(4, 2, 3, 6, 4))
+
+	require.Equal(t, []int{1, 5, 4, 6}, s.ToArray())
+}
+
+func TestSetOperationsRecover(t *testing.T) {
+	panicking := func(i int) int {
+		boomOn(i, 2)
+		return i
+	}
+	operations := map[string]func(a, b stream.Stream[int]) stream.Stream[int]{
+		"union": func(a, b stream.Stream[int]) stream.Stream[int] { return stream.UnionBy(a, b, panicking) },
+		"intersect": func(a, b stream.Stream[int]) stream.Stream[int] {
+			return stream.IntersectBy(a, b, panicking)
+		},
+		"except": func(a, b stream.Stream[int]) stream.Stream[int] { return stream.ExceptBy(a, b, panicking) },
+		"symmetric difference": func(a, b stream.Stream[int]) stream.Stream[int] {
+			return stream.SymmetricDifferenceBy(a, b, panicking)
+		},
+	}
+	for name, operation := range operations {
+		operation := operation
+		t.Run(name, func(t *testing.T) {
+			s := operation(stream.Of(1, 3).Recover(), stream.Of(3, 2))
+
+			require.Nil(t, s.ToArray())
+			requireBoom(t, s.Err())
+		})
+	}
+}
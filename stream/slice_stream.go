@@ -1,6 +1,7 @@
 package stream
 
 import (
+	"fmt"
 	"sort"
 )
 
@@ -8,55 +9,155 @@ import (
 var _ Stream[int] = (*SliceStream[int])(nil)
 
 type SliceStream[T any] struct {
-	elements      []T
-	closeHandlers []func()
+	elements []T
+	// closer is shared by all the streams derived from the same stream, so that the close handlers run once.
+	// It is created when it is first needed, so that the zero value is an empty stream.
+	closer  *closer
+	recover bool
+	err     error
+	// sortBy is the comparator of the sort deferred by SortedWithComparator until the elements are needed.
+	// The elements are not owned by the stream until then, so they are copied before being sorted.
+	sortBy func(T, T) int
 }
 
 func newSliceStream[T any](elements ...T) *SliceStream[T] {
 	return &SliceStream[T]{elements: elements}
 }
 
+// deriveSliceStream returns a stream with the given elements that shares the close handlers
+// and inherits the Recover mode and the error of s.
+func deriveSliceStream[T any, R any](s *SliceStream[T], elements []R, err error) *SliceStream[R] {
+	if err == nil {
+		err = s.err
+	}
+	return &SliceStream[R]{
+		elements: elements,
+		closer:   s.sharedCloser(),
+		recover:  s.recover,
+		err:      err,
+	}
+}
+
+// closer holds the close handlers of the streams derived from the same stream.
+type closer struct {
+	handlers []func()
+	closed   bool
+}
+
+// sharedCloser returns the closer of s, creating it if needed.
+func (s *SliceStream[T]) sharedCloser() *closer {
+	if s.closer == nil {
+		s.closer = &closer{}
+	}
+	return s.closer
+}
+
+// each calls f for each element of this stream until f returns false.
+//
+// If the stream has already failed f is not called and the error is returned.
+// In Recover mode a panic in f stops the iteration and is returned as a *CallbackPanicError.
+func (s *SliceStream[T]) each(f func(T) bool) error {
+	elements := s.items()
+	if s.err != nil {
+		return s.err
+	}
+	for i, el := range elements {
+		el := el
+		next := true
+		if err := catch(s.recover, int64(i), func() { next = f(el) }); err != nil {
+			return err
+		}
+		if !next {
+			break
+		}
+	}
+	return nil
+}
+
+// items returns the elements of this stream, after performing the sort deferred by SortedWithComparator, if any.
+// All the operations that read the elements must read them through it.
+func (s *SliceStream[T]) items() []T {
+	if s.sortBy == nil || s.err != nil {
+		return s.elements
+	}
+	sortable := sortable[T]{data: append([]T{}, s.elements...), comparator: s.sortBy}
+	s.sortBy = nil
+	if err := catch(s.recover, -1, func() { sort.Sort(sortable) }); err != nil {
+		s.elements = []T{}
+		s.err = err
+		return s.elements
+	}
+	s.elements = sortable.data
+	return s.elements
+}
+
+// failed records err (if not nil) and reports whether the stream has failed.
+// A failed stream is closed, so that its close handlers are guaranteed to run.
+func (s *SliceStream[T]) failed(err error) bool {
+	if err != nil && s.err == nil {
+		s.err = err
+	}
+	if s.err == nil {
+		return false
+	}
+	s.Close()
+	return true
+}
+
 // AllMatch returns whether all elements of this stream match the provided predicate.
 //
 //	java: boolean allMatch(Predicate<? super T> predicate)
 func (s *SliceStream[T]) AllMatch(predicate func(T) bool) bool {
-	for _, el := range s.elements {
-		if !predicate(el) {
-			return false
-		}
+	res := true
+	err := s.each(func(el T) bool {
+		res = predicate(el)
+		return res
+	})
+	if s.failed(err) {
+		return false
 	}
-	return true
+	return res
 }
 
 // AnyMatch returns whether any elements of this stream match the provided predicate.
 //
 //	java: boolean anyMatch(Predicate<? super T> predicate)
 func (s *SliceStream[T]) AnyMatch(predicate func(T) bool) bool {
-	for _, el := range s.elements {
-		if predicate(el) {
-			return true
-		}
+	res := false
+	err := s.each(func(el T) bool {
+		res = predicate(el)
+		return !res
+	})
+	if s.failed(err) {
+		return false
 	}
-	return false
+	return res
 }
 
 // NoneMatch returns whether no elements of this stream match the provided predicate.
 //
 //	java: boolean noneMatch(Predicate<? super T> predicate)
 func (s *SliceStream[T]) NoneMatch(predicate func(T) bool) bool {
-	for _, el := range s.elements {
-		if predicate(el) {
-			return false
-		}
+	res := true
+	err := s.each(func(el T) bool {
+		res = !predicate(el)
+		return res
+	})
+	if s.failed(err) {
+		return false
 	}
-	return true
+	return res
 }
 
 // Count returns the count of elements in this stream.
 //
 //	java: long count()
 func (s *SliceStream[T]) Count() int64 {
-	return int64(len(s.elements))
+	elements := s.items()
+	if s.failed(nil) {
+		return 0
+	}
+	return int64(len(elements))
 }
 
 // Distinct returns a stream consisting of the distinct elements (according to the "==" operator) of this stream.
@@ -77,12 +178,13 @@ func (s *SliceStream[T]) Distinct() Stream[T] {
 //	java: Stream<T> filter(Predicate<? super T> predicate)
 func (s *SliceStream[T]) Filter(predicate func(T) bool) Stream[T] {
 	newEl := make([]T, 0, len(s.elements))
-	for _, el := range s.elements {
+	err := s.each(func(el T) bool {
 		if predicate(el) {
 			newEl = append(newEl, el)
 		}
-	}
-	return newSliceStream(newEl...)
+		return true
+	})
+	return deriveSliceStream(s, newEl, err)
 }
 
 // FindAny returns a pointer describing some element of the stream, or a nil pointer if the stream is empty.
@@ -96,8 +198,12 @@ func (s *SliceStream[T]) FindAny() *T {
 //
 //	java: Optional<T> findFirst()
 func (s *SliceStream[T]) FindFirst() *T {
-	if len(s.elements) > 0 {
-		return &s.elements[0]
+	elements := s.items()
+	if s.failed(nil) {
+		return nil
+	}
+	if len(elements) > 0 {
+		return &elements[0]
 	}
 	return nil
 }
@@ -126,9 +232,11 @@ func (s *SliceStream[T]) FlatMapToDouble(mapper func(T) Stream[float64]) Stream[
 //
 //	java: void forEach(Consumer<? super T> action)
 func (s *SliceStream[T]) ForEach(consumer func(T)) {
-	for _, el := range s.elements {
+	err := s.each(func(el T) bool {
 		consumer(el)
-	}
+		return true
+	})
+	s.failed(err)
 }
 
 // ForEachOrdered performs an action for each element of this stream, in the encounter order of the stream if the stream has a defined encounter order.
@@ -140,12 +248,34 @@ func (s *SliceStream[T]) ForEachOrdered(consumer func(T)) {
 
 // Limit returns a stream consisting of the elements of this stream, truncated to be no longer than maxSize in length.
 //
+// It panics if maxSize is negative.
+//
+// NOTE: If this stream is the result of SortedWithComparator, only the maxSize smallest elements are sorted, as in BottomK.
+//
 //	java: Stream<T> limit(long maxSize)
 func (s *SliceStream[T]) Limit(maxSize int64) Stream[T] {
-	if int(maxSize) > len(s.elements) {
+	if maxSize < 0 {
+		panic(fmt.Sprintf("stream: invalid maxSize %d", maxSize))
+	}
+
+	if s.sortBy != nil && s.err == nil && maxSize < int64(len(s.elements)) {
+		h := newBoundedHeap(int(maxSize), s.sortBy)
+		err := catch(s.recover, -1, func() {
+			for _, el := range s.elements {
+				h.add(el)
+			}
+		})
+		if err != nil {
+			return deriveSliceStream(s, []T{}, err)
+		}
+		return deriveSliceStream(s, h.sorted(), nil)
+	}
+
+	elements := s.items()
+	if maxSize > int64(len(elements)) {
 		return s
 	}
-	return &SliceStream[T]{elements: s.elements[0:maxSize]}
+	return deriveSliceStream(s, elements[0:maxSize], nil)
 }
 
 // MapToInt returns an Stream[int64] consisting of the results of applying the given function to the elements of this stream.
@@ -167,15 +297,20 @@ func (s *SliceStream[T]) MapToDouble(mapper func(T) float64) Stream[float64] {
 //
 //	java: Optional<T> max(Comparator<? super T> comparator)
 func (s *SliceStream[T]) Max(comparator func(T, T) int) *T {
-	if len(s.elements) == 0 {
+	elements := s.items()
+	if s.failed(nil) || len(elements) == 0 {
 		return nil
 	}
-	max := s.elements[0]
-	s.ForEach(func(t T) {
+	max := elements[0]
+	err := s.each(func(t T) bool {
 		if comparator(t, max) > 0 {
 			max = t
 		}
+		return true
 	})
+	if s.failed(err) {
+		return nil
+	}
 	return &max
 }
 
@@ -183,15 +318,20 @@ func (s *SliceStream[T]) Max(comparator func(T, T) int) *T {
 //
 //	java: Optional<T> min(Comparator<? super T> comparator)
 func (s *SliceStream[T]) Min(comparator func(T, T) int) *T {
-	if s == nil || len(s.elements) == 0 {
+	elements := s.items()
+	if s == nil || s.failed(nil) || len(elements) == 0 {
 		return nil
 	}
-	min := s.elements[0]
-	s.ForEach(func(t T) {
+	min := elements[0]
+	err := s.each(func(t T) bool {
 		if comparator(t, min) < 0 {
 			min = t
 		}
+		return true
 	})
+	if s.failed(err) {
+		return nil
+	}
 	return &min
 }
 
@@ -199,8 +339,12 @@ func (s *SliceStream[T]) Min(comparator func(T, T) int) *T {
 //
 //	java: Stream<T> peek(Consumer<? super T> action)
 func (s *SliceStream[T]) Peek(action func(T)) Stream[T] {
-	for _, el := range s.elements {
+	err := s.each(func(el T) bool {
 		action(el)
+		return true
+	})
+	if err != nil {
+		return deriveSliceStream(s, []T{}, err)
 	}
 	return s
 }
@@ -209,15 +353,22 @@ func (s *SliceStream[T]) Peek(action func(T)) Stream[T] {
 //
 //	java: Optional<T> reduce(BinaryOperator<T> accumulator)
 func (s *SliceStream[T]) Reduce(accumulator func(T, T) T) *T {
-	if len(s.elements) == 0 {
+	elements := s.items()
+	if s.failed(nil) || len(elements) == 0 {
 		return nil
 	}
-	res := s.elements[0]
-	for i, el := range s.elements {
-		if i == 0 {
-			continue
+	var res T
+	first := true
+	err := s.each(func(el T) bool {
+		if first {
+			res, first = el, false
+			return true
 		}
 		res = accumulator(res, el)
+		return true
+	})
+	if s.failed(err) {
+		return nil
 	}
 	return &res
 }
@@ -229,9 +380,14 @@ func (s *SliceStream[T]) Reduce(accumulator func(T, T) T) *T {
 //	java: T reduce(T identity, BinaryOperator<T> accumulator)
 func (s *SliceStream[T]) ReduceWithIdentity(identity T, accumulator func(T, T) T) T {
 	result := identity
-	s.ForEach(func(t T) {
+	err := s.each(func(t T) bool {
 		result = accumulator(result, t)
+		return true
 	})
+	if s.failed(err) {
+		var zero T
+		return zero
+	}
 	return result
 }
 
@@ -239,10 +395,11 @@ func (s *SliceStream[T]) ReduceWithIdentity(identity T, accumulator func(T, T) T
 //
 // java: Stream<T> skip(long n)
 func (s *SliceStream[T]) Skip(n int64) Stream[T] {
-	if len(s.elements) < int(n) {
-		return newSliceStream[T]()
+	elements := s.items()
+	if int64(len(elements)) < n {
+		return deriveSliceStream(s, []T{}, nil)
 	}
-	return newSliceStream(s.elements[n:]...)
+	return deriveSliceStream(s, elements[n:], nil)
 }
 
 // Sorted returns a stream consisting of the elements of this stream, sorted according to natural order.
@@ -260,12 +417,18 @@ func (s *SliceStream[T]) Sorted() Stream[T] {
 //
 // NOTE: In Java this method overloads the "sorted" method, but Go does not support method overloads, so we need to change the name.
 //
+// NOTE: The sort is deferred until the elements are needed,
+// so that if the next operation is Limit only the elements that are kept are sorted.
+//
 //	java: Stream<T> sorted(Comparator<? super T> comparator)
 func (s *SliceStream[T]) SortedWithComparator(comparator func(T, T) int) Stream[T] {
-	sortable := sortable[T]{data: s.ToArray(), comparator: comparator}
-	sort.Sort(sortable)
-
-	return newSliceStream(sortable.data...)
+	elements := s.items()
+	if s.err != nil {
+		return deriveSliceStream(s, []T{}, nil)
+	}
+	sorted := deriveSliceStream(s, elements, nil)
+	sorted.sortBy = comparator
+	return sorted
 }
 
 // ToArray returns an array containing the elements of this stream.
@@ -279,7 +442,11 @@ func (s *SliceStream[T]) SortedWithComparator(comparator func(T, T) int) Stream[
 //	java: Object[] toArray()
 //	java: <A> A[] toArray(IntFunction<A[]> generator)
 func (s *SliceStream[T]) ToArray() []T {
-	return s.elements
+	elements := s.items()
+	if s.failed(nil) {
+		return nil
+	}
+	return elements
 }
 
 // Methods inherited from BaseStream:
@@ -288,7 +455,12 @@ func (s *SliceStream[T]) ToArray() []T {
 //
 //	java: void close()
 func (s *SliceStream[T]) Close() {
-	for _, closeHandler := range s.closeHandlers {
+	c := s.sharedCloser()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	for _, closeHandler := range c.handlers {
 		closeHandler()
 	}
 }
@@ -306,17 +478,23 @@ func (s *SliceStream[T]) IsParallel() bool {
 //
 //	java: Iterator<T> iterator()
 func (s *SliceStream[T]) Iterator() Iterator[T] {
-	panic("TODO: implement")
+	next := sliceSource(s.ToArray())
+	return &pullIterator[T]{pull: func() (T, bool) {
+		el, ok, _ := next()
+		return el, ok
+	}}
 }
 
 // OnClose returns an equivalent stream with an additional close handler.
 //
+// The close handlers are shared by all the streams derived from the same stream,
+// and run once, when any of them is closed.
+//
 //	java: S onClose(Runnable closeHandler)
 func (s *SliceStream[T]) OnClose(closeHandler func()) Stream[T] {
-	return &SliceStream[T]{
-		elements:      s.elements,
-		closeHandlers: append(s.closeHandlers, closeHandler),
-	}
+	c := s.sharedCloser()
+	c.handlers = append(c.handlers, closeHandler)
+	return s
 }
 
 // Parallel returns an equivalent stream that is parallel.
@@ -343,7 +521,48 @@ func (s *SliceStream[T]) Sequential() Stream[T] {
 //
 //	java: Spliterator<T> spliterator()
 func (s *SliceStream[T]) Spliterator() Spliterator[T] {
-	panic("TODO: implement")
+	return &sliceSpliterator[T]{elements: s.ToArray()}
+}
+
+// sliceSpliterator is a Spliterator over the elements of a slice.
+// It is split in halves, like the Spliterator of a Java array.
+type sliceSpliterator[T any] struct {
+	elements []T
+}
+
+// TryAdvance performs the given action on the next element, if there is one, and reports whether there was.
+//
+//	java: boolean tryAdvance(Consumer<? super T> action)
+func (sp *sliceSpliterator[T]) TryAdvance(action func(T)) bool {
+	if len(sp.elements) == 0 {
+		return false
+	}
+	el := sp.elements[0]
+	sp.elements = sp.elements[1:]
+	action(el)
+	return true
+}
+
+// ForEachRemaining performs the given action on each remaining element.
+//
+//	java: void forEachRemaining(Consumer<? super T> action)
+func (sp *sliceSpliterator[T]) ForEachRemaining(action func(T)) {
+	for sp.TryAdvance(action) {
+	}
+}
+
+// TrySplit returns a Spliterator covering the first half of the remaining elements,
+// or nil if there are less than 2 of them.
+//
+//	java: Spliterator<T> trySplit()
+func (sp *sliceSpliterator[T]) TrySplit() Spliterator[T] {
+	if len(sp.elements) < 2 {
+		return nil
+	}
+	mid := len(sp.elements) / 2
+	prefix := &sliceSpliterator[T]{elements: sp.elements[:mid]}
+	sp.elements = sp.elements[mid:]
+	return prefix
 }
 
 // Unordered returns an equivalent stream that is unordered.
@@ -355,3 +574,28 @@ func (s *SliceStream[T]) Spliterator() Spliterator[T] {
 func (s *SliceStream[T]) Unordered() Stream[T] {
 	return s
 }
+
+// Recover returns an equivalent stream in which panics in the user-provided functions are recovered.
+//
+// When a function panics, the operation stops, the close handlers of the stream are called,
+// the terminal operation returns the zero value of its result
+// and Err returns a *CallbackPanicError describing the panic.
+//
+// The functions passed to the intermediate operations are called eagerly, when the operation is called,
+// and a panic is reported by the terminal operation of the stream returned by it.
+func (s *SliceStream[T]) Recover() Stream[T] {
+	return &SliceStream[T]{
+		elements: s.elements,
+		closer:   s.sharedCloser(),
+		recover:  true,
+		err:      s.err,
+		sortBy:   s.sortBy,
+	}
+}
+
+// Err returns the error that caused this stream to stop, if any.
+//
+// Like bufio.Scanner.Err, it should be checked after the terminal operation has been executed.
+func (s *SliceStream[T]) Err() error {
+	return s.err
+}
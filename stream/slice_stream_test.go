@@ -310,3 +310,29 @@ func TestSliceStream(t *testing.T) {
 		_ = s.Parallel()
 	})
 }
+
+func TestZeroValueSliceStream(t *testing.T) {
+	var s SliceStream[int]
+
+	require.Equal(t, int64(0), s.Count())
+	require.Empty(t, s.ToArray())
+	require.Nil(t, s.FindFirst())
+	require.NotPanics(t, s.Close)
+	require.NoError(t, s.Err())
+}
+
+func TestSliceSpliterator(t *testing.T) {
+	sp := newSliceStream(1, 2, 3, 4, 5).Spliterator()
+
+	prefix := sp.TrySplit()
+	require.NotNil(t, prefix)
+	var first, rest []int
+	require.True(t, prefix.TryAdvance(func(i int) { first = append(first, i) }))
+	prefix.ForEachRemaining(func(i int) { first = append(first, i) })
+	require.False(t, prefix.TryAdvance(func(i int) { first = append(first, i) }))
+	sp.ForEachRemaining(func(i int) { rest = append(rest, i) })
+
+	require.Equal(t, []int{1, 2}, first)
+	require.Equal(t, []int{3, 4, 5}, rest)
+	require.Nil(t, sp.TrySplit())
+}
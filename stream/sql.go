@@ -0,0 +1,83 @@
+package stream
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// FromRows returns a lazy stream of the rows of rows, each one scanned into a T by scan.
+//
+// The rows are closed by the close handler of the stream, so the stream should be closed once it is no longer needed.
+// Errors returned by scan and by rows.Err stop the stream and are reported by Err.
+//
+// NOTE: There is no such thing in the Java standard library.
+func FromRows[T any](rows *sql.Rows, scan func(*sql.Rows) (T, error)) Stream[T] {
+	s := newIteratorStream(func() (T, bool, error) {
+		var zero T
+		if !rows.Next() {
+			return zero, false, rows.Err()
+		}
+		el, err := scan(rows)
+		if err != nil {
+			return zero, false, err
+		}
+		return el, true, nil
+	})
+	s.OnClose(func() { _ = rows.Close() })
+	return s
+}
+
+// InsertBatches writes the elements of the stream to db in batches of batchSize elements.
+//
+// For each batch, buildStmt returns the statement and its arguments (e.g. a multi-row INSERT),
+// which is executed in its own transaction.
+// If a batch fails, its transaction is rolled back and no more batches are written,
+// but the batches written before that stay committed.
+//
+// It is a terminal operation. It returns the first database error, or the error of the stream.
+// It panics if batchSize is not positive.
+//
+// NOTE: There is no such thing in the Java standard library.
+func InsertBatches[T any](ctx context.Context, db *sql.DB, stream Stream[T], batchSize int, buildStmt func(batch []T) (string, []any)) error {
+	if batchSize <= 0 {
+		panic(fmt.Sprintf("stream: invalid batch size %d", batchSize))
+	}
+
+	var batches int
+	insert := func(batch []T) error {
+		query, args := buildStmt(batch)
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("stream: beginning batch %d: %w", batches, err)
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("stream: inserting batch %d: %w", batches, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("stream: committing batch %d: %w", batches, err)
+		}
+		batches++
+		return nil
+	}
+
+	batch := make([]T, 0, batchSize)
+	err := forEachUntilErr(stream, func(t T) error {
+		batch = append(batch, t)
+		if len(batch) < batchSize {
+			return nil
+		}
+		err := insert(batch)
+		batch = make([]T, 0, batchSize)
+		return err
+	})
+	if err != nil || len(batch) == 0 {
+		return err
+	}
+	if err := insert(batch); err != nil {
+		stream.Close()
+		return err
+	}
+	return nil
+}
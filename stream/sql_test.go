@@ -0,0 +1,247 @@
+package stream_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromRows(t *testing.T) {
+	db := openFakeDB(t, [][]driver.Value{{int64(1), "alice"}, {int64(2), "bob"}, {int64(3), "carol"}})
+	scan := func(rows *sql.Rows) (string, error) {
+		var (
+			id   int
+			name string
+		)
+		err := rows.Scan(&id, &name)
+		return fmt.Sprintf("%d:%s", id, name), err
+	}
+
+	t.Run("scan rows", func(t *testing.T) {
+		rows, err := db.Query("SELECT id, name FROM users")
+		require.NoError(t, err)
+		s := stream.FromRows(rows, scan)
+		defer s.Close()
+
+		require.Equal(t, []string{"1:alice", "2:bob", "3:carol"}, s.ToArray())
+		require.NoError(t, s.Err())
+	})
+
+	t.Run("close releases the rows", func(t *testing.T) {
+		rows, err := db.Query("SELECT id, name FROM users")
+		require.NoError(t, err)
+		s := stream.FromRows(rows, scan)
+
+		require.Equal(t, "1:alice", *s.FindFirst())
+		s.Close()
+		require.False(t, rows.Next())
+		require.NoError(t, rows.Err())
+	})
+
+	t.Run("scan error", func(t *testing.T) {
+		rows, err := db.Query("SELECT id, name FROM users")
+		require.NoError(t, err)
+		s := stream.FromRows(rows, func(rows *sql.Rows) (string, error) {
+			var id string
+			return id, rows.Scan(&id)
+		})
+
+		require.Nil(t, s.ToArray())
+		require.Error(t, s.Err())
+	})
+
+	t.Run("rows error", func(t *testing.T) {
+		rows, err := db.Query("SELECT id, name FROM users FAIL")
+		require.NoError(t, err)
+		s := stream.FromRows(rows, scan)
+
+		require.Nil(t, s.ToArray())
+		require.EqualError(t, s.Err(), "connection lost")
+	})
+}
+
+func TestInsertBatches(t *testing.T) {
+	buildStmt := func(batch []int) (string, []any) {
+		args := make([]any, 0, len(batch))
+		for _, i := range batch {
+			args = append(args, int64(i))
+		}
+		return "INSERT INTO numbers VALUES " + strings.TrimSuffix(strings.Repeat("(?),", len(batch)), ","), args
+	}
+
+	t.Run("batches", func(t *testing.T) {
+		db := openFakeDB(t, nil)
+
+		err := stream.InsertBatches(context.Background(), db, stream.Of(1, 2, 3, 4, 5), 2, buildStmt)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{
+			"BEGIN",
+			"INSERT INTO numbers VALUES (?),(?) [1 2]",
+			"COMMIT",
+			"BEGIN",
+			"INSERT INTO numbers VALUES (?),(?) [3 4]",
+			"COMMIT",
+			"BEGIN",
+			"INSERT INTO numbers VALUES (?) [5]",
+			"COMMIT",
+		}, fakeDBs[t.Name()].log)
+	})
+
+	t.Run("failed batch is rolled back", func(t *testing.T) {
+		db := openFakeDB(t, nil)
+		var closed bool
+		s := stream.Of(1, 2, 3, 4, 5).OnClose(func() { closed = true })
+
+		err := stream.InsertBatches(context.Background(), db, s, 2, func(batch []int) (string, []any) {
+			query, args := buildStmt(batch)
+			if batch[0] == 3 {
+				query += " FAIL"
+			}
+			return query, args
+		})
+		require.ErrorContains(t, err, "inserting batch 1")
+		require.True(t, closed)
+
+		require.Equal(t, []string{
+			"BEGIN",
+			"INSERT INTO numbers VALUES (?),(?) [1 2]",
+			"COMMIT",
+			"BEGIN",
+			"ROLLBACK",
+		}, fakeDBs[t.Name()].log)
+	})
+
+	t.Run("failed last batch closes the stream", func(t *testing.T) {
+		db := openFakeDB(t, nil)
+		var closed bool
+		s := stream.Of(1, 2, 3).OnClose(func() { closed = true })
+
+		err := stream.InsertBatches(context.Background(), db, s, 2, func(batch []int) (string, []any) {
+			query, args := buildStmt(batch)
+			if len(batch) == 1 {
+				query += " FAIL"
+			}
+			return query, args
+		})
+		require.ErrorContains(t, err, "inserting batch 1")
+		require.True(t, closed)
+	})
+
+	t.Run("failed transaction", func(t *testing.T) {
+		db := openFakeDB(t, nil)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := stream.InsertBatches(ctx, db, stream.Of(1, 2, 3), 2, buildStmt)
+		require.ErrorIs(t, err, context.Canceled)
+		require.ErrorContains(t, err, "beginning batch 0")
+		require.Empty(t, fakeDBs[t.Name()].log)
+	})
+
+	t.Run("empty stream", func(t *testing.T) {
+		db := openFakeDB(t, nil)
+
+		require.NoError(t, stream.InsertBatches(context.Background(), db, stream.Empty[int](), 2, buildStmt))
+		require.Empty(t, fakeDBs[t.Name()].log)
+	})
+}
+
+// The fake driver below supports just enough of database/sql/driver for the tests:
+// every query returns the rows of the database and every statement is recorded in its log.
+// Statements ending with "FAIL" fail.
+
+var (
+	fakeDBsMu sync.Mutex
+	fakeDBs   = map[string]*fakeDB{}
+)
+
+func init() {
+	sql.Register("stream-fake", fakeDriver{})
+}
+
+func openFakeDB(t *testing.T, rows [][]driver.Value) *sql.DB {
+	fakeDBsMu.Lock()
+	fakeDBs[t.Name()] = &fakeDB{rows: rows}
+	fakeDBsMu.Unlock()
+
+	db, err := sql.Open("stream-fake", t.Name())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+type fakeDB struct {
+	rows [][]driver.Value
+	log  []string
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	fakeDBsMu.Lock()
+	defer fakeDBsMu.Unlock()
+	return &fakeConn{db: fakeDBs[name]}, nil
+}
+
+type fakeConn struct{ db *fakeDB }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{db: c.db, query: query}, nil
+}
+func (c *fakeConn) Close() error { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.db.log = append(c.db.log, "BEGIN")
+	return &fakeTx{db: c.db}, nil
+}
+
+type fakeTx struct{ db *fakeDB }
+
+func (tx *fakeTx) Commit() error   { tx.db.log = append(tx.db.log, "COMMIT"); return nil }
+func (tx *fakeTx) Rollback() error { tx.db.log = append(tx.db.log, "ROLLBACK"); return nil }
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if strings.HasSuffix(s.query, "FAIL") {
+		return nil, errors.New("constraint violation")
+	}
+	s.db.log = append(s.db.log, fmt.Sprintf("%s %v", s.query, args))
+	return driver.RowsAffected(len(args)), nil
+}
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &fakeRows{rows: s.db.rows, fail: strings.HasSuffix(s.query, "FAIL")}, nil
+}
+
+type fakeRows struct {
+	rows [][]driver.Value
+	fail bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"id", "name"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.fail {
+		return errors.New("connection lost")
+	}
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,183 @@
+package stream
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidEvent is returned by ServeSSE when an Event cannot be written, because its ID or type contains a line break.
+var ErrInvalidEvent = errors.New("stream: invalid event")
+
+// Event is a Server-Sent Event, as described in https://html.spec.whatwg.org/multipage/server-sent-events.html.
+type Event struct {
+	// ID is the event ID. When parsing, it is the last event ID seen in the stream, as required by the specification.
+	ID string
+	// Event is the event type. An empty type means "message".
+	Event string
+	// Data is the payload of the event. Multi-line payloads are separated by "\n".
+	// When writing, "\r\n" and "\r" are line separators too.
+	Data string
+	// Retry is the reconnection time. It is 0 if not set.
+	Retry time.Duration
+}
+
+// FromSSE returns a lazy stream of the events read from r, which is a "text/event-stream" body.
+//
+// Comments and events without data are skipped, as required by the specification.
+// Read errors stop the stream and are reported by Err.
+//
+// NOTE: There is no such thing in the Java standard library.
+func FromSSE(r io.Reader) Stream[Event] {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(scanSSELines)
+	var lastID string
+
+	return newIteratorStream(func() (Event, bool, error) {
+		var (
+			ev      Event
+			data    strings.Builder
+			hasData bool
+		)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line == "" {
+				if !hasData {
+					ev = Event{}
+					continue
+				}
+				ev.ID = lastID
+				ev.Data = strings.TrimSuffix(data.String(), "\n")
+				return ev, true, nil
+			}
+			if strings.HasPrefix(line, ":") {
+				continue
+			}
+
+			field, value := line, ""
+			if i := strings.IndexByte(line, ':'); i >= 0 {
+				field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+			}
+			switch field {
+			case "event":
+				ev.Event = value
+			case "data":
+				data.WriteString(value)
+				data.WriteByte('\n')
+				hasData = true
+			case "id":
+				if !strings.ContainsRune(value, 0) {
+					lastID = value
+				}
+			case "retry":
+				if ms, err := strconv.ParseUint(value, 10, 63); err == nil {
+					ev.Retry = time.Duration(ms) * time.Millisecond
+				}
+			}
+		}
+		// An incomplete event at the end of the input is discarded, as required by the specification.
+		return Event{}, false, scanner.Err()
+	})
+}
+
+// scanSSELines is a bufio.SplitFunc that splits on "\r\n", "\n" or "\r".
+func scanSSELines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\n' {
+			return i + 1, data[:i], nil
+		}
+		if i+1 < len(data) {
+			if data[i+1] == '\n' {
+				return i + 2, data[:i], nil
+			}
+			return i + 1, data[:i], nil
+		}
+		if atEOF {
+			return i + 1, data[:i], nil
+		}
+		// Need more data to know whether "\r" is followed by "\n".
+		return 0, nil, nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// ServeSSE writes the elements of the stream to w as Server-Sent Events, each one converted to an Event by encode.
+// Each event is flushed as soon as it is written, if w supports it.
+//
+// Writing stops when ctx is done, which should be the context of the request, so that it stops when the client disconnects.
+// The stream is closed in that case.
+// Note that the context is checked between the elements, so a stream that blocks waiting for its next element
+// is not interrupted.
+//
+// It is a terminal operation. It returns ctx.Err(), the first encoding or write error, or the error of the stream.
+// An event whose ID or type contains a line break is an ErrInvalidEvent error, because it cannot be written.
+//
+// NOTE: There is no such thing in the Java standard library.
+func ServeSSE[T any](ctx context.Context, w http.ResponseWriter, stream Stream[T], encode func(T) (Event, error)) error {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	if flusher != nil {
+		flusher.Flush()
+	}
+
+	return forEachUntilErr(stream, func(t T) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ev, err := encode(t)
+		if err != nil {
+			return err
+		}
+		formatted, err := formatEvent(ev)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, formatted); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+}
+
+// formatEvent returns ev in the "text/event-stream" format.
+// The lines of Data are written as separate data fields, and a line break in ID or Event is an error.
+func formatEvent(ev Event) (string, error) {
+	if strings.ContainsAny(ev.ID, "\r\n") {
+		return "", fmt.Errorf("%w: ID %q contains a line break", ErrInvalidEvent, ev.ID)
+	}
+	if strings.ContainsAny(ev.Event, "\r\n") {
+		return "", fmt.Errorf("%w: type %q contains a line break", ErrInvalidEvent, ev.Event)
+	}
+
+	var sb strings.Builder
+	if ev.ID != "" {
+		sb.WriteString("id: " + ev.ID + "\n")
+	}
+	if ev.Event != "" {
+		sb.WriteString("event: " + ev.Event + "\n")
+	}
+	if ev.Retry > 0 {
+		sb.WriteString("retry: " + strconv.FormatInt(ev.Retry.Milliseconds(), 10) + "\n")
+	}
+	data := strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(ev.Data)
+	for _, line := range strings.Split(data, "\n") {
+		sb.WriteString("data: " + line + "\n")
+	}
+	sb.WriteString("\n")
+	return sb.String(), nil
+}
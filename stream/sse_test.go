@@ -0,0 +1,126 @@
+package stream_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromSSE(t *testing.T) {
+	body := ": keep-alive\r\n" +
+		"retry: 3000\r\n" +
+		"id: 1\r\n" +
+		"event: update\r\n" +
+		"data: first line\r\n" +
+		"data:second line\r\n" +
+		"\r\n" +
+		"event: empty\n" +
+		"\n" +
+		"data\r" +
+		"\r" +
+		"id: 3\n" +
+		"data: {\"cpu\": 0.5}\n" +
+		"\n" +
+		"data: incomplete"
+
+	s := stream.FromSSE(strings.NewReader(body))
+
+	require.Equal(t, []stream.Event{
+		{ID: "1", Event: "update", Data: "first line\nsecond line", Retry: 3 * time.Second},
+		{ID: "1", Data: ""},
+		{ID: "3", Data: `{"cpu": 0.5}`},
+	}, s.ToArray())
+	require.NoError(t, s.Err())
+}
+
+func TestServeSSE(t *testing.T) {
+	encode := func(i int) (stream.Event, error) {
+		return stream.Event{ID: strconv.Itoa(i), Event: "tick", Data: "value " + strconv.Itoa(i)}, nil
+	}
+
+	t.Run("over HTTP", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = stream.ServeSSE(r.Context(), w, stream.Of(1, 2, 3), encode)
+		}))
+		defer srv.Close()
+
+		resp, err := http.Get(srv.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		events := stream.FromSSE(resp.Body).ToArray()
+		require.Equal(t, []stream.Event{
+			{ID: "1", Event: "tick", Data: "value 1"},
+			{ID: "2", Event: "tick", Data: "value 2"},
+			{ID: "3", Event: "tick", Data: "value 3"},
+		}, events)
+	})
+
+	t.Run("multi-line data", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		err := stream.ServeSSE(context.Background(), rec, stream.Of("a\nb"), func(s string) (stream.Event, error) {
+			return stream.Event{Data: s, Retry: time.Second}, nil
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "retry: 1000\ndata: a\ndata: b\n\n", rec.Body.String())
+		require.True(t, rec.Flushed)
+	})
+
+	t.Run("CR line breaks in data", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		err := stream.ServeSSE(context.Background(), rec, stream.Of("a\r\nb\rc"), func(s string) (stream.Event, error) {
+			return stream.Event{Data: s}, nil
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "data: a\ndata: b\ndata: c\n\n", rec.Body.String())
+		require.Equal(t, "a\nb\nc", stream.FromSSE(rec.Body).FindFirst().Data)
+	})
+
+	t.Run("line breaks in ID or type", func(t *testing.T) {
+		for _, ev := range []stream.Event{{ID: "1\ndata: injected", Data: "x"}, {Event: "tick\r", Data: "x"}} {
+			rec := httptest.NewRecorder()
+			err := stream.ServeSSE(context.Background(), rec, stream.Of(ev), func(e stream.Event) (stream.Event, error) {
+				return e, nil
+			})
+
+			require.ErrorIs(t, err, stream.ErrInvalidEvent)
+			require.Empty(t, rec.Body.String())
+		}
+	})
+
+	t.Run("client disconnects", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var closed bool
+		s := stream.Lines(strings.NewReader("1\n2\n3\n4\n")).
+			Peek(func(line string) {
+				if line == "3" {
+					cancel()
+				}
+			}).
+			OnClose(func() { closed = true })
+
+		rec := httptest.NewRecorder()
+		err := stream.ServeSSE(ctx, rec, s, func(line string) (stream.Event, error) {
+			i, err := strconv.Atoi(line)
+			if err != nil {
+				return stream.Event{}, err
+			}
+			return encode(i)
+		})
+
+		require.ErrorIs(t, err, context.Canceled)
+		require.True(t, closed)
+		require.Equal(t, []string{"value 1", "value 2"}, stream.Map(stream.FromSSE(rec.Body), func(e stream.Event) string { return e.Data }).ToArray())
+	})
+}
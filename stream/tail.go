@@ -0,0 +1,202 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTailInterval is the interval at which Tail polls the file, unless configured otherwise.
+const DefaultTailInterval = 250 * time.Millisecond
+
+// TailOptions configures Tail.
+type TailOptions struct {
+	// FromEnd makes the stream start at the end of the file, so that only the lines appended after that are returned.
+	FromEnd bool
+	// Interval is the interval at which the file is polled for changes.
+	// If it is 0, DefaultTailInterval is used.
+	Interval time.Duration
+	// Clock is used for waiting between the polls. If it is nil, SystemClock is used.
+	Clock Clock
+}
+
+// Tail opens the file at path and returns an infinite lazy stream of its lines, like "tail -F".
+//
+// Once the end of the file is reached, the file is polled for appended lines.
+// If the file is truncated, it is read again from its beginning.
+// If the file is rotated (i.e. path refers to a different file than before),
+// the rest of the old file is read, and then the new file is opened and read from its beginning.
+// An incomplete last line is returned only once it is terminated by a newline, or once its file has been rotated.
+//
+// The stream ends when it is closed, and stops with ctx.Err() when ctx is done.
+// The file is closed by the close handler of the stream, so the stream should be closed once it is no longer needed.
+//
+// NOTE: There is no such thing in the Java standard library.
+func Tail(ctx context.Context, path string, opts TailOptions) (Stream[string], error) {
+	if opts.Interval == 0 {
+		opts.Interval = DefaultTailInterval
+	}
+	if opts.Clock == nil {
+		opts.Clock = SystemClock
+	}
+
+	t := &tailer{ctx: ctx, path: path, opts: opts, stop: make(chan struct{})}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	var offset int64
+	if opts.FromEnd {
+		if offset, err = f.Seek(0, io.SeekEnd); err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+	}
+	t.setFile(f, offset)
+
+	return newIteratorStream(t.next).OnClose(t.close), nil
+}
+
+// tailer reads the lines of a file that is being appended to.
+type tailer struct {
+	ctx  context.Context
+	path string
+	opts TailOptions
+
+	// mu guards f and rotated, which are closed by the close handler of the stream, possibly while the stream is waiting.
+	mu      sync.Mutex
+	f       *os.File
+	r       *bufio.Reader
+	offset  int64
+	partial string
+	// rotated is the file that replaced f, which is read once the rest of f has been read.
+	rotated *os.File
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func (t *tailer) next() (string, bool, error) {
+	for {
+		select {
+		case <-t.stop:
+			return "", false, nil
+		case <-t.ctx.Done():
+			return "", false, t.ctx.Err()
+		default:
+		}
+
+		line, err := t.r.ReadString('\n')
+		t.offset += int64(len(line))
+		if err == nil {
+			line = strings.TrimSuffix(t.partial+line, "\n")
+			t.partial = ""
+			return strings.TrimSuffix(line, "\r"), true, nil
+		}
+		t.partial += line
+		if err != io.EOF {
+			if t.stopped() {
+				return "", false, nil
+			}
+			return "", false, err
+		}
+
+		if t.rotated != nil {
+			// The old file has been read to its end, so its incomplete last line will never be terminated.
+			line := t.partial
+			t.setFile(t.rotated, 0)
+			if line != "" {
+				return strings.TrimSuffix(line, "\r"), true, nil
+			}
+			continue
+		}
+
+		changed, err := t.checkFile()
+		if err != nil {
+			return "", false, err
+		}
+		if changed {
+			continue
+		}
+
+		select {
+		case <-t.ctx.Done():
+			return "", false, t.ctx.Err()
+		case <-t.stop:
+			return "", false, nil
+		case <-t.opts.Clock.After(t.opts.Interval):
+		}
+	}
+}
+
+// checkFile detects whether the file has been truncated or rotated.
+// A truncated file is read again from its beginning, and a rotated file is opened, to be read after the rest of the current one.
+// It returns true if the file should be read again.
+func (t *tailer) checkFile() (bool, error) {
+	info, err := os.Stat(t.path)
+	if err != nil {
+		// The file has been removed and the new one has not been created yet.
+		return false, nil
+	}
+	current, err := t.f.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	if !os.SameFile(info, current) {
+		f, err := os.Open(t.path)
+		if err != nil {
+			return false, nil
+		}
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.rotated = f
+		return true, nil
+	}
+	if info.Size() < t.offset {
+		if _, err := t.f.Seek(0, io.SeekStart); err != nil {
+			return false, err
+		}
+		t.setFile(t.f, 0)
+		return true, nil
+	}
+	return false, nil
+}
+
+// setFile makes f the file that is read, starting from its current position, which is offset.
+func (t *tailer) setFile(f *os.File, offset int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.f != nil && t.f != f {
+		_ = t.f.Close()
+	}
+	if t.rotated == f {
+		t.rotated = nil
+	}
+	t.f, t.r, t.offset, t.partial = f, bufio.NewReader(f), offset, ""
+}
+
+func (t *tailer) stopped() bool {
+	select {
+	case <-t.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *tailer) close() {
+	t.stopOnce.Do(func() {
+		close(t.stop)
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		_ = t.f.Close()
+		if t.rotated != nil {
+			_ = t.rotated.Close()
+		}
+	})
+}
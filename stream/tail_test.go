@@ -0,0 +1,157 @@
+package stream_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+// stepClock is a Clock that does not wait.
+// Instead, each call to After runs the next of the given steps, which simulate what happens while waiting.
+type stepClock struct {
+	now   time.Time
+	steps []func()
+	waits []time.Duration
+}
+
+func (c *stepClock) Now() time.Time { return c.now }
+
+func (c *stepClock) After(d time.Duration) <-chan time.Time {
+	c.waits = append(c.waits, d)
+	if len(c.steps) > 0 {
+		step := c.steps[0]
+		c.steps = c.steps[1:]
+		step()
+	}
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func appendFile(t *testing.T, path, content string) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+}
+
+func TestTail(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	appendFile(t, path, "a\nb\n")
+
+	t.Run("follow", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		clock := &stepClock{steps: []func(){
+			func() { appendFile(t, path, "c\n") },
+			func() {},
+			func() { appendFile(t, path, "partial") },
+			func() { appendFile(t, path, " line\r\n") },
+			func() { require.NoError(t, os.WriteFile(path, []byte("truncated\n"), 0o600)) },
+			func() {
+				require.NoError(t, os.Rename(path, path+".1"))
+				appendFile(t, path+".1", "appended before rotation\n")
+			},
+			func() { appendFile(t, path, "rotated\n") },
+			cancel,
+		}}
+
+		s, err := stream.Tail(ctx, path, stream.TailOptions{Interval: time.Second, Clock: clock})
+		require.NoError(t, err)
+		defer s.Close()
+
+		var lines []string
+		s.ForEach(func(line string) { lines = append(lines, line) })
+
+		require.Equal(t, []string{"a", "b", "c", "partial line", "truncated", "appended before rotation", "rotated"}, lines)
+		require.ErrorIs(t, s.Err(), context.Canceled)
+		require.Equal(t, time.Second, clock.waits[0])
+	})
+
+	t.Run("incomplete line before rotation", func(t *testing.T) {
+		path := filepath.Join(dir, "rotating.log")
+		appendFile(t, path, "a\n")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		clock := &stepClock{steps: []func(){
+			func() { appendFile(t, path, "unterminated") },
+			func() {
+				require.NoError(t, os.Rename(path, path+".1"))
+				appendFile(t, path, "b\n")
+			},
+			cancel,
+		}}
+
+		s, err := stream.Tail(ctx, path, stream.TailOptions{Clock: clock})
+		require.NoError(t, err)
+		defer s.Close()
+
+		var lines []string
+		s.ForEach(func(line string) { lines = append(lines, line) })
+
+		require.Equal(t, []string{"a", "unterminated", "b"}, lines)
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		s, err := stream.Tail(ctx, path, stream.TailOptions{})
+		require.NoError(t, err)
+		defer s.Close()
+
+		require.Nil(t, s.FindFirst())
+		require.ErrorIs(t, s.Err(), context.Canceled)
+	})
+
+	t.Run("from end", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte("old\n"), 0o600))
+		clock := &stepClock{steps: []func(){
+			func() { appendFile(t, path, "new\n") },
+		}}
+
+		s, err := stream.Tail(context.Background(), path, stream.TailOptions{FromEnd: true, Clock: clock})
+		require.NoError(t, err)
+		defer s.Close()
+
+		require.Equal(t, "new", *s.FindFirst())
+		require.Equal(t, stream.DefaultTailInterval, clock.waits[0])
+	})
+
+	t.Run("close", func(t *testing.T) {
+		var s stream.Stream[string]
+		clock := &stepClock{steps: []func(){
+			func() { s.Close() },
+		}}
+
+		s, err := stream.Tail(context.Background(), path, stream.TailOptions{FromEnd: true, Clock: clock})
+		require.NoError(t, err)
+
+		require.Equal(t, int64(0), s.Count())
+		require.NoError(t, s.Err())
+	})
+
+	t.Run("close while waiting", func(t *testing.T) {
+		s, err := stream.Tail(context.Background(), path, stream.TailOptions{FromEnd: true, Interval: time.Hour})
+		require.NoError(t, err)
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			s.Close()
+		}()
+		require.Nil(t, s.FindFirst())
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := stream.Tail(context.Background(), filepath.Join(dir, "missing.log"), stream.TailOptions{})
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
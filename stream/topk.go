@@ -0,0 +1,94 @@
+package stream
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+)
+
+// BottomK returns the k smallest elements of the stream according to comparator, in ascending order.
+// Equal elements are in encounter order.
+//
+// It is equivalent to stream.SortedWithComparator(comparator).Limit(k).ToArray(),
+// but only the k smallest elements seen so far are kept, in a heap, so it takes O(k) memory and O(n log k) time.
+//
+// It panics if k is negative.
+//
+// NOTE: There is no such thing in the Java standard library.
+func BottomK[T any](stream Stream[T], k int, comparator func(T, T) int) []T {
+	if k < 0 {
+		panic(fmt.Sprintf("stream: invalid k %d", k))
+	}
+
+	h := newBoundedHeap(k, comparator)
+	stream.ForEach(h.add)
+	if stream.Err() != nil {
+		return nil
+	}
+	return h.sorted()
+}
+
+// TopK returns the k greatest elements of the stream according to comparator, in descending order.
+// Equal elements are in encounter order.
+//
+// Like BottomK, it takes O(k) memory.
+//
+// It panics if k is negative.
+//
+// NOTE: There is no such thing in the Java standard library.
+func TopK[T any](stream Stream[T], k int, comparator func(T, T) int) []T {
+	return BottomK(stream, k, func(a, b T) int { return comparator(b, a) })
+}
+
+// boundedHeap keeps the k smallest elements added to it, according to comparator and then to the order they were added in.
+// It is a max-heap, so that the greatest of them is the one that is replaced by a smaller element.
+type boundedHeap[T any] struct {
+	items      []Indexed[T]
+	k          int
+	added      int64
+	comparator func(T, T) int
+}
+
+func newBoundedHeap[T any](k int, comparator func(T, T) int) *boundedHeap[T] {
+	return &boundedHeap[T]{k: k, comparator: comparator}
+}
+
+// add adds el to the heap, if it is one of the k smallest elements so far.
+func (h *boundedHeap[T]) add(el T) {
+	item := Indexed[T]{Index: h.added, Value: el}
+	h.added++
+	switch {
+	case len(h.items) < h.k:
+		heap.Push(h, item)
+	case h.k > 0 && h.less(item, h.items[0]):
+		h.items[0] = item
+		heap.Fix(h, 0)
+	}
+}
+
+// sorted returns the elements of the heap in ascending order.
+func (h *boundedHeap[T]) sorted() []T {
+	sort.Slice(h.items, func(i, j int) bool { return h.less(h.items[i], h.items[j]) })
+	res := make([]T, len(h.items))
+	for i, item := range h.items {
+		res[i] = item.Value
+	}
+	return res
+}
+
+func (h *boundedHeap[T]) less(a, b Indexed[T]) bool {
+	if c := h.comparator(a.Value, b.Value); c != 0 {
+		return c < 0
+	}
+	return a.Index < b.Index
+}
+
+func (h *boundedHeap[T]) Len() int           { return len(h.items) }
+func (h *boundedHeap[T]) Less(i, j int) bool { return h.less(h.items[j], h.items[i]) }
+func (h *boundedHeap[T]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *boundedHeap[T]) Push(x any)         { h.items = append(h.items, x.(Indexed[T])) }
+func (h *boundedHeap[T]) Pop() any {
+	item := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return item
+}
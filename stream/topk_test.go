@@ -0,0 +1,113 @@
+package stream_test
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+type score struct {
+	Player string
+	Points int
+}
+
+func byPoints(a, b score) int { return a.Points - b.Points }
+
+func scores() stream.Stream[score] {
+	return stream.Of(score{"ada", 30}, score{"alan", 50}, score{"grace", 10}, score{"linus", 50}, score{"ken", 40})
+}
+
+func TestTopK(t *testing.T) {
+	require.Equal(t, []score{{"alan", 50}, {"linus", 50}, {"ken", 40}}, stream.TopK(scores(), 3, byPoints))
+	require.Len(t, stream.TopK(scores(), 10, byPoints), 5)
+	require.Empty(t, stream.TopK(scores(), 0, byPoints))
+	require.Panics(t, func() { stream.TopK(scores(), -1, byPoints) })
+}
+
+func TestBottomK(t *testing.T) {
+	require.Equal(t, []int{1, 2, 2}, stream.BottomK(stream.Of(5, 2, 9, 1, 2, 7), 3, compareInts))
+
+	t.Run("lazy stream", func(t *testing.T) {
+		lines := stream.Lines(strings.NewReader("pear\napple\nfig\nbanana\n"))
+
+		require.Equal(t, []string{"apple", "banana"}, stream.BottomK(lines, 2, strings.Compare))
+	})
+
+	t.Run("recover", func(t *testing.T) {
+		s := stream.Of(1, 2, 3).Recover()
+		res := stream.BottomK(s, 1, func(a, b int) int { panic("boom") })
+
+		require.Nil(t, res)
+		require.Error(t, s.Err())
+	})
+}
+
+func TestSortedWithComparatorLimit(t *testing.T) {
+	t.Run("slice stream", func(t *testing.T) {
+		sorted := stream.Of(5, 2, 9, 1, 7).SortedWithComparator(compareInts)
+
+		require.Equal(t, []int{1, 2}, sorted.Limit(2).ToArray())
+		require.Equal(t, []int{1, 2, 5, 7, 9}, sorted.Limit(10).ToArray())
+		require.Equal(t, []int{1, 2, 5, 7, 9}, sorted.ToArray())
+		require.Equal(t, []int{5, 7}, sorted.Skip(2).Limit(2).ToArray())
+	})
+
+	t.Run("slice stream compares less", func(t *testing.T) {
+		elements := make([]int, 1000)
+		for i := range elements {
+			elements[i] = (i * 7919) % 1000
+		}
+		comparisons := 0
+		counting := func(a, b int) int {
+			comparisons++
+			return a - b
+		}
+
+		require.Equal(t, []int{0, 1, 2}, stream.Of(elements...).SortedWithComparator(counting).Limit(3).ToArray())
+		require.Less(t, comparisons, 3000)
+	})
+
+	t.Run("slice stream does not copy the elements", func(t *testing.T) {
+		elements := make([]int, 1<<20)
+		for i := range elements {
+			elements[i] = len(elements) - i
+		}
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		res := stream.Of(elements...).SortedWithComparator(compareInts).Limit(3).ToArray()
+		runtime.ReadMemStats(&after)
+
+		require.Equal(t, []int{1, 2, 3}, res)
+		require.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(len(elements)))
+		require.Equal(t, len(elements), elements[0], "the source must not be sorted in place")
+	})
+
+	t.Run("lazy stream", func(t *testing.T) {
+		lines := stream.Lines(strings.NewReader("pear\napple\nfig\nbanana\n"))
+
+		require.Equal(t, []string{"apple", "banana", "fig"}, lines.SortedWithComparator(strings.Compare).Limit(3).ToArray())
+	})
+
+	t.Run("recover", func(t *testing.T) {
+		s := stream.Of(3, 1, 2).Recover().SortedWithComparator(func(a, b int) int { panic("boom") }).Limit(1)
+
+		require.Nil(t, s.ToArray())
+		require.Error(t, s.Err())
+	})
+
+	t.Run("recover count", func(t *testing.T) {
+		s := stream.Of(3, 1, 2).Recover().SortedWithComparator(func(a, b int) int { panic("boom") })
+
+		require.Equal(t, int64(0), s.Count())
+		require.Error(t, s.Err())
+	})
+}
+
+func TestLimitNegative(t *testing.T) {
+	require.Panics(t, func() { stream.Of(3, 1, 2).Limit(-1) })
+	require.Panics(t, func() { stream.Of(3, 1, 2).SortedWithComparator(compareInts).Limit(-1) })
+	require.Panics(t, func() { stream.Lines(strings.NewReader("a\n")).Limit(-1) })
+}
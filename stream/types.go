@@ -2,11 +2,42 @@ package stream
 
 // This file contains more types that have to be defined in order for the Stream methods to be able to be implemented.
 
-// TODO(asankov): this is to be implemented
-type Iterator[T any] interface{}
+// Iterator is an iterator over the elements of a stream.
+//
+//	java: interface Iterator<E>
+type Iterator[T any] interface {
+	// HasNext returns true if the iteration has more elements.
+	//
+	// 	java: boolean hasNext()
+	HasNext() bool
 
-// TODO(asankov): this is to be implemented
-type Spliterator[T any] interface{}
+	// Next returns the next element in the iteration.
+	// It panics if the iteration has no more elements.
+	//
+	// 	java: E next()
+	Next() T
+}
+
+// Spliterator is an object for traversing and partitioning the elements of a stream.
+//
+//	java: interface Spliterator<T>
+type Spliterator[T any] interface {
+	// TryAdvance performs the given action on the next element, if there is one, and reports whether there was.
+	//
+	// 	java: boolean tryAdvance(Consumer<? super T> action)
+	TryAdvance(action func(T)) bool
+
+	// ForEachRemaining performs the given action on each remaining element.
+	//
+	// 	java: void forEachRemaining(Consumer<? super T> action)
+	ForEachRemaining(action func(T))
+
+	// TrySplit returns a Spliterator covering a prefix of the remaining elements, which this Spliterator no longer covers,
+	// or nil if the elements cannot be partitioned.
+	//
+	// 	java: Spliterator<T> trySplit()
+	TrySplit() Spliterator[T]
+}
 
 // Supplier represents a supplier of results.
 //
@@ -61,3 +92,29 @@ type StreamBuilder[T any] struct{}
 
 // TODO(asankov): this is to be implemented
 type UnaryOperator[T any] struct{}
+
+// Number is a constraint that permits any integer or floating-point type.
+//
+// NOTE: There is no such thing in the Java standard library, where the numeric streams are separate types (IntStream, DoubleStream, etc.).
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Ordered is a constraint that permits any type that supports the "<" operator.
+type Ordered interface {
+	Number | ~string
+}
+
+// Indexed is an element of a stream with its index in the stream.
+type Indexed[T any] struct {
+	Index int64
+	Value T
+}
+
+// Pair is a pair of values of possibly different types.
+type Pair[A any, B any] struct {
+	First  A
+	Second B
+}
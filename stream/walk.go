@@ -0,0 +1,188 @@
+package stream
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+)
+
+// ErrFileSystemLoop is reported by the entries of a walk that follows symbolic links
+// when a link points to one of its ancestor directories.
+//
+//	java: class FileSystemLoopException
+var ErrFileSystemLoop = errors.New("stream: file system loop detected")
+
+// WalkEntry is an element of the streams returned by Walk, Find and List.
+type WalkEntry struct {
+	// Path is the path of the entry, relative to the root of the file system
+	// (in the form expected by fs.FS, e.g. "dir/file.txt").
+	Path string
+	// Entry describes the file or directory. It is nil if the root could not be stat-ed.
+	Entry fs.DirEntry
+	// Depth is the depth of the entry relative to the starting directory, which has depth 0.
+	Depth int
+	// Err is set if the entry could not be visited.
+	// For directories that cannot be read, the entry is yielded once without an error
+	// and once more with the error, like fs.WalkDir does.
+	Err error
+}
+
+// WalkOptions configures the streams returned by WalkWithOptions and Find.
+type WalkOptions struct {
+	// MaxDepth is the maximum number of levels of directories to visit.
+	// If it is 0, there is no limit.
+	MaxDepth int
+	// FollowSymlinks makes the walk descend into symbolic links to directories.
+	// Links that create a cycle are reported with ErrFileSystemLoop.
+	FollowSymlinks bool
+}
+
+// Walk returns a lazy stream of the files and directories in the file tree rooted at root,
+// including root itself.
+//
+// The tree is visited depth-first, in lexical order, which is the same order in which fs.WalkDir visits it.
+// Directories are read only when the stream reaches them.
+//
+//	java: static Stream<Path> walk(Path start, FileVisitOption... options) (Files)
+func Walk(fsys fs.FS, root string) Stream[WalkEntry] {
+	return WalkWithOptions(fsys, root, WalkOptions{})
+}
+
+// WalkWithOptions returns a lazy stream of the files and directories in the file tree rooted at root,
+// including root itself, visited according to the given options.
+//
+//	java: static Stream<Path> walk(Path start, int maxDepth, FileVisitOption... options) (Files)
+func WalkWithOptions(fsys fs.FS, root string, opts WalkOptions) Stream[WalkEntry] {
+	w := &walker{fsys: fsys, root: root, opts: opts}
+	return newIteratorStream(w.next)
+}
+
+// Find returns a lazy stream of the entries in the file tree rooted at root that match the given matcher.
+//
+// Entries with an error are always included, so that errors are not silently dropped.
+//
+//	java: static Stream<Path> find(Path start, int maxDepth, BiPredicate<Path,BasicFileAttributes> matcher, FileVisitOption... options) (Files)
+func Find(fsys fs.FS, root string, opts WalkOptions, matcher func(WalkEntry) bool) Stream[WalkEntry] {
+	return WalkWithOptions(fsys, root, opts).Filter(func(e WalkEntry) bool {
+		return e.Err != nil || matcher(e)
+	})
+}
+
+// List returns a lazy stream of the entries of the directory dir. The listing is not recursive.
+//
+// If dir cannot be read, the stream contains a single entry describing the error.
+//
+//	java: static Stream<Path> list(Path dir) (Files)
+func List(fsys fs.FS, dir string) Stream[WalkEntry] {
+	return WalkWithOptions(fsys, dir, WalkOptions{MaxDepth: 1}).Filter(func(e WalkEntry) bool {
+		return e.Depth > 0 || e.Err != nil
+	})
+}
+
+// walker performs a depth-first traversal of a file tree, one entry at a time.
+type walker struct {
+	fsys fs.FS
+	root string
+	opts WalkOptions
+
+	started bool
+	// expand is the last yielded directory, which is read on the next call to next.
+	expand *WalkEntry
+	stack  []*walkFrame
+}
+
+// walkFrame holds the entries of a directory that have not been visited yet.
+type walkFrame struct {
+	dir     string
+	info    fs.FileInfo
+	entries []fs.DirEntry
+	depth   int
+}
+
+func (w *walker) next() (WalkEntry, bool, error) {
+	if !w.started {
+		w.started = true
+		info, err := fs.Stat(w.fsys, w.root)
+		if err != nil {
+			return WalkEntry{Path: w.root, Err: err}, true, nil
+		}
+		e := WalkEntry{Path: w.root, Entry: fs.FileInfoToDirEntry(info)}
+		if info.IsDir() {
+			w.expand = &e
+		}
+		return e, true, nil
+	}
+
+	for {
+		if w.expand != nil {
+			e := *w.expand
+			w.expand = nil
+			if errEntry, ok := w.push(e); !ok {
+				return errEntry, true, nil
+			}
+		}
+		if len(w.stack) == 0 {
+			return WalkEntry{}, false, nil
+		}
+
+		top := w.stack[len(w.stack)-1]
+		if len(top.entries) == 0 {
+			w.stack = w.stack[:len(w.stack)-1]
+			continue
+		}
+		de := top.entries[0]
+		top.entries = top.entries[1:]
+
+		e := WalkEntry{Path: path.Join(top.dir, de.Name()), Entry: de, Depth: top.depth}
+		if w.opts.MaxDepth == 0 || e.Depth < w.opts.MaxDepth {
+			descend, err := w.shouldDescend(e)
+			if err != nil {
+				e.Err = err
+			} else if descend {
+				w.expand = &e
+			}
+		}
+		return e, true, nil
+	}
+}
+
+// push reads the directory described by e and pushes its entries on the stack.
+// If the directory cannot be read, it returns an entry describing the error and false.
+func (w *walker) push(e WalkEntry) (WalkEntry, bool) {
+	entries, err := fs.ReadDir(w.fsys, e.Path)
+	if err != nil {
+		e.Err = err
+		return e, false
+	}
+	frame := &walkFrame{dir: e.Path, entries: entries, depth: e.Depth + 1}
+	if w.opts.FollowSymlinks {
+		frame.info, _ = fs.Stat(w.fsys, e.Path)
+	}
+	w.stack = append(w.stack, frame)
+	return WalkEntry{}, true
+}
+
+// shouldDescend reports whether the directory described by e should be visited.
+func (w *walker) shouldDescend(e WalkEntry) (bool, error) {
+	if e.Entry.IsDir() {
+		return true, nil
+	}
+	if !w.opts.FollowSymlinks || e.Entry.Type()&fs.ModeSymlink == 0 {
+		return false, nil
+	}
+
+	info, err := fs.Stat(w.fsys, e.Path)
+	if err != nil {
+		return false, err
+	}
+	if !info.IsDir() {
+		return false, nil
+	}
+	for _, frame := range w.stack {
+		if frame.info != nil && os.SameFile(frame.info, info) {
+			return false, ErrFileSystemLoop
+		}
+	}
+	return true, nil
+}
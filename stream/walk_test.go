@@ -0,0 +1,124 @@
+package stream_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+func paths(s stream.Stream[stream.WalkEntry]) []string {
+	return stream.Map(s, func(e stream.WalkEntry) string { return e.Path }).ToArray()
+}
+
+func TestWalk(t *testing.T) {
+	fsys := fstest.MapFS{
+		"go.mod":               {Data: []byte("module x")},
+		"cmd/tool/main.go":     {Data: []byte("package main")},
+		"internal/a/a.go":      {Data: []byte("package a")},
+		"internal/a/a_test.go": {Data: []byte("package a")},
+		"internal/b.go":        {Data: []byte("package internal")},
+	}
+
+	t.Run("Walk", func(t *testing.T) {
+		require.Equal(t, []string{
+			".",
+			"cmd",
+			"cmd/tool",
+			"cmd/tool/main.go",
+			"go.mod",
+			"internal",
+			"internal/a",
+			"internal/a/a.go",
+			"internal/a/a_test.go",
+			"internal/b.go",
+		}, paths(stream.Walk(fsys, ".")))
+	})
+
+	t.Run("same order as fs.WalkDir", func(t *testing.T) {
+		var expected []string
+		require.NoError(t, fs.WalkDir(fsys, "internal", func(path string, d fs.DirEntry, err error) error {
+			expected = append(expected, path)
+			return err
+		}))
+
+		require.Equal(t, expected, paths(stream.Walk(fsys, "internal")))
+	})
+
+	t.Run("MaxDepth", func(t *testing.T) {
+		s := stream.WalkWithOptions(fsys, ".", stream.WalkOptions{MaxDepth: 1})
+
+		require.Equal(t, []string{".", "cmd", "go.mod", "internal"}, paths(s))
+	})
+
+	t.Run("Depth", func(t *testing.T) {
+		e := stream.Walk(fsys, ".").Filter(func(e stream.WalkEntry) bool { return e.Path == "internal/a/a.go" }).FindFirst()
+
+		require.NotNil(t, e)
+		require.Equal(t, 3, e.Depth)
+		require.False(t, e.Entry.IsDir())
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		s := stream.Find(fsys, ".", stream.WalkOptions{}, func(e stream.WalkEntry) bool {
+			return strings.HasSuffix(e.Path, "_test.go")
+		})
+
+		require.Equal(t, []string{"internal/a/a_test.go"}, paths(s))
+	})
+
+	t.Run("List", func(t *testing.T) {
+		require.Equal(t, []string{"internal/a", "internal/b.go"}, paths(stream.List(fsys, "internal")))
+	})
+
+	t.Run("missing root", func(t *testing.T) {
+		entries := stream.Walk(fsys, "missing").ToArray()
+
+		require.Len(t, entries, 1)
+		require.ErrorIs(t, entries[0].Err, fs.ErrNotExist)
+	})
+
+	t.Run("lazy traversal", func(t *testing.T) {
+		first := stream.Walk(fsys, ".").Skip(1).FindFirst()
+
+		require.Equal(t, "cmd", first.Path)
+	})
+}
+
+func TestWalkFollowSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "real", "sub"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "real", "sub", "file.txt"), nil, 0o600))
+	if err := os.Symlink(filepath.Join(dir, "real"), filepath.Join(dir, "link")); err != nil {
+		t.Skipf("symlinks are not supported: %v", err)
+	}
+	require.NoError(t, os.Symlink(filepath.Join(dir, "real"), filepath.Join(dir, "real", "sub", "loop")))
+	fsys := os.DirFS(dir)
+
+	t.Run("not followed by default", func(t *testing.T) {
+		require.Equal(t, []string{
+			".",
+			"link",
+			"real",
+			"real/sub",
+			"real/sub/file.txt",
+			"real/sub/loop",
+		}, paths(stream.Walk(fsys, ".")))
+	})
+
+	t.Run("followed", func(t *testing.T) {
+		entries := stream.WalkWithOptions(fsys, "real", stream.WalkOptions{FollowSymlinks: true}).ToArray()
+
+		require.Len(t, entries, 4)
+		require.Equal(t, "real/sub/loop", entries[3].Path)
+		require.ErrorIs(t, entries[3].Err, stream.ErrFileSystemLoop)
+
+		s := stream.WalkWithOptions(fsys, "link", stream.WalkOptions{FollowSymlinks: true, MaxDepth: 2})
+		require.Equal(t, []string{"link", "link/sub", "link/sub/file.txt", "link/sub/loop"}, paths(s))
+	})
+}
@@ -0,0 +1,222 @@
+package stream
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FileOp is the kind of change described by a FileEvent.
+type FileOp int
+
+const (
+	// FileCreated means that the file did not exist in the previous snapshot.
+	FileCreated FileOp = iota + 1
+	// FileModified means that the size, the modification time or the mode of the file has changed.
+	FileModified
+	// FileRemoved means that the file does not exist anymore.
+	FileRemoved
+)
+
+func (op FileOp) String() string {
+	switch op {
+	case FileCreated:
+		return "created"
+	case FileModified:
+		return "modified"
+	case FileRemoved:
+		return "removed"
+	}
+	return "unknown"
+}
+
+// FileEvent is an element of the stream returned by WatchDir.
+type FileEvent struct {
+	// Path is the path of the file, i.e. the watched directory joined with the path of the file inside of it.
+	Path string
+	// Op is the kind of change.
+	Op FileOp
+	// Info describes the file. It is nil if the file has been removed.
+	Info fs.FileInfo
+}
+
+// WatchOptions configures WatchDirWithOptions.
+type WatchOptions struct {
+	// Interval is the interval at which the directory is polled. It must be positive, otherwise WatchDirWithOptions panics.
+	Interval time.Duration
+	// Recursive makes the subdirectories be watched as well.
+	Recursive bool
+	// Debounce, if positive, delays the events for a file until it has not changed for that long.
+	// Subsequent changes of the same file are merged into a single event (e.g. created and then modified is created).
+	Debounce time.Duration
+	// Clock is used for waiting between the polls. If it is nil, SystemClock is used.
+	Clock Clock
+}
+
+// WatchDir returns an infinite lazy stream of the changes to the files in the directory at path,
+// computed by comparing snapshots of the directory taken every interval.
+//
+// Changes of the modification time of directories are not reported.
+// The events found in a single snapshot are ordered by path.
+// The stream ends when it is closed.
+//
+// NOTE: There is no such thing in the Java standard library.
+// The closest thing is WatchService, which uses the notification APIs of the operating system.
+func WatchDir(path string, interval time.Duration) (Stream[FileEvent], error) {
+	return WatchDirWithOptions(path, WatchOptions{Interval: interval})
+}
+
+// WatchDirWithOptions is like WatchDir, but it watches the directory according to the given options.
+func WatchDirWithOptions(path string, opts WatchOptions) (Stream[FileEvent], error) {
+	if opts.Interval <= 0 {
+		panic(fmt.Sprintf("stream: invalid watch interval %v", opts.Interval))
+	}
+	if opts.Clock == nil {
+		opts.Clock = SystemClock
+	}
+	if _, err := os.ReadDir(path); err != nil {
+		return nil, err
+	}
+
+	w := &watcher{
+		root:    path,
+		opts:    opts,
+		pending: map[string]*pendingEvent{},
+		stop:    make(chan struct{}),
+	}
+	w.snapshot = w.takeSnapshot()
+
+	return newIteratorStream(w.next).OnClose(w.close), nil
+}
+
+// watcher computes file events by diffing snapshots of a directory.
+type watcher struct {
+	root string
+	opts WatchOptions
+
+	snapshot map[string]fs.FileInfo
+	pending  map[string]*pendingEvent
+	queue    []FileEvent
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// pendingEvent is an event that is being debounced.
+type pendingEvent struct {
+	FileEvent
+	changed time.Time
+}
+
+func (w *watcher) next() (FileEvent, bool, error) {
+	for len(w.queue) == 0 {
+		select {
+		case <-w.stop:
+			return FileEvent{}, false, nil
+		case <-w.opts.Clock.After(w.opts.Interval):
+		}
+		w.poll()
+	}
+	ev := w.queue[0]
+	w.queue = w.queue[1:]
+	return ev, true, nil
+}
+
+// poll takes a new snapshot and queues the events that are ready.
+func (w *watcher) poll() {
+	now := w.opts.Clock.Now()
+	current := w.takeSnapshot()
+	changes := diffSnapshots(w.snapshot, current)
+	w.snapshot = current
+
+	if w.opts.Debounce <= 0 {
+		w.queue = append(w.queue, changes...)
+		return
+	}
+
+	for _, ev := range changes {
+		p, ok := w.pending[ev.Path]
+		if !ok {
+			w.pending[ev.Path] = &pendingEvent{FileEvent: ev, changed: now}
+			continue
+		}
+		p.changed = now
+		p.Info = ev.Info
+		switch {
+		case p.Op == FileCreated && ev.Op == FileRemoved:
+			delete(w.pending, ev.Path)
+		case p.Op == FileCreated:
+			// Created and then modified is still created.
+		case p.Op == FileRemoved && ev.Op == FileCreated:
+			p.Op = FileModified
+		default:
+			p.Op = ev.Op
+		}
+	}
+
+	var ready []FileEvent
+	for path, p := range w.pending {
+		if now.Sub(p.changed) >= w.opts.Debounce {
+			ready = append(ready, p.FileEvent)
+			delete(w.pending, path)
+		}
+	}
+	sort.Slice(ready, func(i, j int) bool { return ready[i].Path < ready[j].Path })
+	w.queue = append(w.queue, ready...)
+}
+
+// takeSnapshot returns the files in the watched directory.
+// Files that cannot be read are left out of the snapshot.
+func (w *watcher) takeSnapshot() map[string]fs.FileInfo {
+	snapshot := map[string]fs.FileInfo{}
+	if !w.opts.Recursive {
+		entries, _ := os.ReadDir(w.root)
+		for _, e := range entries {
+			if info, err := e.Info(); err == nil {
+				snapshot[filepath.Join(w.root, e.Name())] = info
+			}
+		}
+		return snapshot
+	}
+
+	_ = filepath.WalkDir(w.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == w.root {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			snapshot[path] = info
+		}
+		return nil
+	})
+	return snapshot
+}
+
+// diffSnapshots returns the events that turn the old snapshot into the new one, ordered by path.
+func diffSnapshots(old, current map[string]fs.FileInfo) []FileEvent {
+	var events []FileEvent
+	for path, info := range current {
+		prev, ok := old[path]
+		switch {
+		case !ok:
+			events = append(events, FileEvent{Path: path, Op: FileCreated, Info: info})
+		case info.IsDir() && prev.IsDir():
+		case info.Size() != prev.Size() || !info.ModTime().Equal(prev.ModTime()) || info.Mode() != prev.Mode():
+			events = append(events, FileEvent{Path: path, Op: FileModified, Info: info})
+		}
+	}
+	for path := range old {
+		if _, ok := current[path]; !ok {
+			events = append(events, FileEvent{Path: path, Op: FileRemoved})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Path < events[j].Path })
+	return events
+}
+
+func (w *watcher) close() {
+	w.stopOnce.Do(func() { close(w.stop) })
+}
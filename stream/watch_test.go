@@ -0,0 +1,114 @@
+package stream_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+type fileChange struct {
+	Path string
+	Op   stream.FileOp
+}
+
+func fileChanges(dir string, s stream.Stream[stream.FileEvent]) []fileChange {
+	return stream.Map(s, func(e stream.FileEvent) fileChange {
+		rel, _ := filepath.Rel(dir, e.Path)
+		return fileChange{Path: filepath.ToSlash(rel), Op: e.Op}
+	}).ToArray()
+}
+
+func TestWatchDir(t *testing.T) {
+	t.Run("created, modified and removed", func(t *testing.T) {
+		dir := t.TempDir()
+		appendFile(t, filepath.Join(dir, "a.txt"), "a")
+		clock := &stepClock{steps: []func(){
+			func() { appendFile(t, filepath.Join(dir, "b.txt"), "b") },
+			func() {},
+			func() { appendFile(t, filepath.Join(dir, "a.txt"), "more") },
+			func() {
+				require.NoError(t, os.Remove(filepath.Join(dir, "b.txt")))
+				require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0o755))
+			},
+			func() { appendFile(t, filepath.Join(dir, "sub", "c.txt"), "c") },
+		}}
+
+		s, err := stream.WatchDirWithOptions(dir, stream.WatchOptions{Interval: time.Second, Clock: clock})
+		require.NoError(t, err)
+		defer s.Close()
+
+		require.Equal(t, []fileChange{
+			{"b.txt", stream.FileCreated},
+			{"a.txt", stream.FileModified},
+			{"b.txt", stream.FileRemoved},
+			{"sub", stream.FileCreated},
+		}, fileChanges(dir, s.Limit(4)))
+		require.Equal(t, "removed", stream.FileRemoved.String())
+	})
+
+	t.Run("recursive", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0o755))
+		clock := &stepClock{steps: []func(){
+			func() { appendFile(t, filepath.Join(dir, "sub", "c.txt"), "c") },
+		}}
+
+		s, err := stream.WatchDirWithOptions(dir, stream.WatchOptions{Interval: time.Second, Recursive: true, Clock: clock})
+		require.NoError(t, err)
+		defer s.Close()
+
+		e := s.FindFirst()
+		require.Equal(t, filepath.Join(dir, "sub", "c.txt"), e.Path)
+		require.Equal(t, stream.FileCreated, e.Op)
+		require.Equal(t, int64(1), e.Info.Size())
+	})
+
+	t.Run("debounce", func(t *testing.T) {
+		dir := t.TempDir()
+		appendFile(t, filepath.Join(dir, "a.txt"), "a")
+		clock := &stepClock{steps: []func(){
+			func() { appendFile(t, filepath.Join(dir, "b.txt"), "b") },
+			func() { appendFile(t, filepath.Join(dir, "b.txt"), "bb") },
+			func() {
+				appendFile(t, filepath.Join(dir, "tmp"), "")
+				require.NoError(t, os.Remove(filepath.Join(dir, "a.txt")))
+			},
+			func() { require.NoError(t, os.Remove(filepath.Join(dir, "tmp"))) },
+			func() {},
+			func() {},
+		}}
+
+		s, err := stream.WatchDirWithOptions(dir, stream.WatchOptions{Interval: time.Second, Debounce: 2 * time.Second, Clock: clock})
+		require.NoError(t, err)
+		defer s.Close()
+
+		require.Equal(t, []fileChange{
+			{"b.txt", stream.FileCreated},
+			{"a.txt", stream.FileRemoved},
+		}, fileChanges(dir, s.Limit(2)))
+		require.Equal(t, 5*time.Second, clock.Now().Sub(time.Time{}))
+	})
+
+	t.Run("close", func(t *testing.T) {
+		var s stream.Stream[stream.FileEvent]
+		clock := &stepClock{steps: []func(){
+			func() { s.Close() },
+		}}
+
+		s, err := stream.WatchDirWithOptions(t.TempDir(), stream.WatchOptions{Interval: time.Second, Clock: clock})
+		require.NoError(t, err)
+
+		require.Equal(t, int64(0), s.Count())
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		_, err := stream.WatchDir(filepath.Join(t.TempDir(), "missing"), time.Second)
+		require.ErrorIs(t, err, os.ErrNotExist)
+
+		require.Panics(t, func() { _, _ = stream.WatchDir(t.TempDir(), 0) })
+	})
+}
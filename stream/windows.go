@@ -0,0 +1,292 @@
+package stream
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Window is an element of the streams returned by TumblingWindows, HoppingWindows and SessionWindows.
+type Window[T any] struct {
+	// Start is the inclusive start of the window.
+	Start time.Time
+	// End is the exclusive end of the window.
+	End time.Time
+	// Elements are the elements of the window, in the order they were pulled from the source stream.
+	Elements []T
+}
+
+// WindowOptions configures the event-time windowing operations.
+//
+// The zero value expects the timestamps to be in order and drops the late elements.
+type WindowOptions[T any] struct {
+	// MaxOutOfOrderness is how much the timestamps can be out of order.
+	// The watermark, the point in event time up to which all elements are assumed to have arrived,
+	// is the greatest timestamp seen so far minus MaxOutOfOrderness.
+	// A window is emitted when the watermark passes its end.
+	MaxOutOfOrderness time.Duration
+	// AllowedLateness is how long a window is kept after it was emitted.
+	// An element that arrives for a window that was emitted but is still kept causes the window
+	// to be emitted again, with all its elements.
+	AllowedLateness time.Duration
+	// Late, if not nil, is called with the elements that arrive after all their windows were dropped.
+	// For sessions, an element is late if its session was dropped and it does not join any other session.
+	// Otherwise these elements are dropped.
+	Late func(T)
+}
+
+// TumblingWindows returns a lazy stream of the fixed-size, non-overlapping windows of the elements of the stream,
+// grouped by the event time returned by timestamp.
+// The windows are aligned as by time.Time.Truncate.
+//
+// The windows are emitted as the watermark passes their end (see WindowOptions), in order of their end,
+// and the remaining ones are emitted when the source stream ends.
+// The result only depends on the order of the elements, so replaying the same input gives the same windows.
+//
+// It panics if size is not positive.
+//
+// NOTE: There is no such thing in the Java standard library.
+func TumblingWindows[T any](stream Stream[T], size time.Duration, timestamp func(T) time.Time) Stream[Window[T]] {
+	return TumblingWindowsWithOptions(stream, size, timestamp, WindowOptions[T]{})
+}
+
+// TumblingWindowsWithOptions is like TumblingWindows, but with the given options.
+func TumblingWindowsWithOptions[T any](stream Stream[T], size time.Duration, timestamp func(T) time.Time, opts WindowOptions[T]) Stream[Window[T]] {
+	return HoppingWindowsWithOptions(stream, size, size, timestamp, opts)
+}
+
+// HoppingWindows returns a lazy stream of the fixed-size windows of the elements of the stream, starting every hop,
+// grouped by the event time returned by timestamp.
+// The windows overlap if hop is less than size, in which case an element belongs to several windows.
+// If hop is greater than size, the elements between the windows are dropped.
+// The windows are aligned as by time.Time.Truncate.
+//
+// The windows are emitted as in TumblingWindows.
+//
+// It panics if size or hop is not positive.
+//
+// NOTE: There is no such thing in the Java standard library.
+func HoppingWindows[T any](stream Stream[T], size, hop time.Duration, timestamp func(T) time.Time) Stream[Window[T]] {
+	return HoppingWindowsWithOptions(stream, size, hop, timestamp, WindowOptions[T]{})
+}
+
+// HoppingWindowsWithOptions is like HoppingWindows, but with the given options.
+func HoppingWindowsWithOptions[T any](stream Stream[T], size, hop time.Duration, timestamp func(T) time.Time, opts WindowOptions[T]) Stream[Window[T]] {
+	if size <= 0 || hop <= 0 {
+		panic(fmt.Sprintf("stream: invalid window size %s and hop %s", size, hop))
+	}
+
+	return windowed(stream, timestamp, &windowOperator[T]{opts: opts, assign: func(ts time.Time) []timeSpan {
+		var spans []timeSpan
+		for start := ts.Truncate(hop); start.Add(size).After(ts); start = start.Add(-hop) {
+			spans = append([]timeSpan{{start, start.Add(size)}}, spans...)
+		}
+		return spans
+	}})
+}
+
+// SessionWindows returns a lazy stream of the session windows of the elements of the stream,
+// grouped by the event time returned by timestamp.
+// A session is a window of elements that are at most gap apart, so it ends gap after its last element.
+// When an element joins two sessions, they are merged.
+//
+// The windows are emitted as in TumblingWindows.
+//
+// It panics if gap is not positive.
+//
+// NOTE: There is no such thing in the Java standard library.
+func SessionWindows[T any](stream Stream[T], gap time.Duration, timestamp func(T) time.Time) Stream[Window[T]] {
+	return SessionWindowsWithOptions(stream, gap, timestamp, WindowOptions[T]{})
+}
+
+// SessionWindowsWithOptions is like SessionWindows, but with the given options.
+func SessionWindowsWithOptions[T any](stream Stream[T], gap time.Duration, timestamp func(T) time.Time, opts WindowOptions[T]) Stream[Window[T]] {
+	if gap <= 0 {
+		panic(fmt.Sprintf("stream: invalid session gap %s", gap))
+	}
+
+	return windowed(stream, timestamp, &windowOperator[T]{opts: opts, merging: true, assign: func(ts time.Time) []timeSpan {
+		return []timeSpan{{ts, ts.Add(gap)}}
+	}})
+}
+
+// windowed returns the lazy stream of the windows of the elements of the stream, as assigned by op.
+func windowed[T any](stream Stream[T], timestamp func(T) time.Time, op *windowOperator[T]) Stream[Window[T]] {
+	s := lazy(stream)
+	var i int64
+	done := false
+	return deriveIteratorStream(s, func() (Window[T], bool) {
+		for len(op.ready) == 0 {
+			if done {
+				return Window[T]{}, false
+			}
+
+			el, ok := s.pull()
+			if !ok {
+				if s.state.err != nil {
+					return Window[T]{}, false
+				}
+				done = true
+				op.fire(true)
+				continue
+			}
+
+			if !s.call(i, func() { op.add(el, timestamp(el), i) }) {
+				return Window[T]{}, false
+			}
+			i++
+			op.fire(false)
+		}
+
+		w := op.ready[0]
+		op.ready = op.ready[1:]
+		return w, true
+	})
+}
+
+// timeSpan is the span of time [start, end).
+type timeSpan struct {
+	start, end time.Time
+}
+
+// intersects reports whether the spans overlap or touch each other.
+func (t timeSpan) intersects(o timeSpan) bool {
+	return !t.start.After(o.end) && !o.start.After(t.end)
+}
+
+// sequenced is an element with its position in the source stream.
+type sequenced[T any] struct {
+	el  T
+	seq int64
+}
+
+// pendingWindow is a window that was not dropped yet.
+type pendingWindow[T any] struct {
+	timeSpan
+	elements []sequenced[T]
+	// dirty is whether the window has elements that were not emitted yet.
+	dirty bool
+}
+
+// windowOperator assigns the elements to windows and decides when they are emitted.
+type windowOperator[T any] struct {
+	opts WindowOptions[T]
+	// assign returns the windows an element with the given timestamp belongs to.
+	assign func(ts time.Time) []timeSpan
+	// merging is whether intersecting windows are merged, as sessions are.
+	merging bool
+
+	windows   []*pendingWindow[T]
+	watermark time.Time
+	started   bool
+	ready     []Window[T]
+}
+
+// expired reports whether the window with the given end is dropped, because the watermark passed its end plus the allowed lateness.
+func (op *windowOperator[T]) expired(end time.Time) bool {
+	return op.started && !end.Add(op.opts.AllowedLateness).After(op.watermark)
+}
+
+// add assigns the element to its windows and advances the watermark.
+func (op *windowOperator[T]) add(el T, ts time.Time, seq int64) {
+	spans := op.assign(ts)
+	added := false
+	for _, span := range spans {
+		if op.expired(span.end) && !(op.merging && op.intersectsAny(span)) {
+			continue
+		}
+		added = true
+		if op.merging {
+			op.merge(span, sequenced[T]{el, seq})
+		} else {
+			w := op.find(span)
+			w.elements = append(w.elements, sequenced[T]{el, seq})
+			w.dirty = true
+		}
+	}
+	if !added && len(spans) > 0 && op.opts.Late != nil {
+		op.opts.Late(el)
+	}
+
+	if watermark := ts.Add(-op.opts.MaxOutOfOrderness); !op.started || watermark.After(op.watermark) {
+		op.watermark = watermark
+		op.started = true
+	}
+}
+
+// find returns the window with the given span, creating it if it does not exist.
+func (op *windowOperator[T]) find(span timeSpan) *pendingWindow[T] {
+	for _, w := range op.windows {
+		if w.start.Equal(span.start) && w.end.Equal(span.end) {
+			return w
+		}
+	}
+	w := &pendingWindow[T]{timeSpan: span}
+	op.windows = append(op.windows, w)
+	return w
+}
+
+// intersectsAny reports whether the span intersects any of the windows.
+func (op *windowOperator[T]) intersectsAny(span timeSpan) bool {
+	for _, w := range op.windows {
+		if w.intersects(span) {
+			return true
+		}
+	}
+	return false
+}
+
+// merge adds a window with the given span and element, merging it with the windows it intersects.
+func (op *windowOperator[T]) merge(span timeSpan, el sequenced[T]) {
+	merged := &pendingWindow[T]{timeSpan: span, elements: []sequenced[T]{el}, dirty: true}
+	windows := make([]*pendingWindow[T], 0, len(op.windows)+1)
+	for _, w := range op.windows {
+		if !w.intersects(merged.timeSpan) {
+			windows = append(windows, w)
+			continue
+		}
+		if w.start.Before(merged.start) {
+			merged.start = w.start
+		}
+		if w.end.After(merged.end) {
+			merged.end = w.end
+		}
+		merged.elements = append(merged.elements, w.elements...)
+	}
+	sort.SliceStable(merged.elements, func(i, j int) bool { return merged.elements[i].seq < merged.elements[j].seq })
+	op.windows = append(windows, merged)
+}
+
+// fire moves the windows that are due to the ready queue and drops the expired ones.
+// If final is true all the windows are due and dropped.
+func (op *windowOperator[T]) fire(final bool) {
+	var fired []*pendingWindow[T]
+	windows := op.windows[:0]
+	for _, w := range op.windows {
+		if w.dirty && (final || !w.end.After(op.watermark)) {
+			fired = append(fired, w)
+			w.dirty = false
+		}
+		if !final && !op.expired(w.end) {
+			windows = append(windows, w)
+		}
+	}
+	for i := len(windows); i < len(op.windows); i++ {
+		op.windows[i] = nil
+	}
+	op.windows = windows
+
+	sort.SliceStable(fired, func(i, j int) bool {
+		if !fired[i].end.Equal(fired[j].end) {
+			return fired[i].end.Before(fired[j].end)
+		}
+		return fired[i].start.Before(fired[j].start)
+	})
+	for _, w := range fired {
+		elements := make([]T, len(w.elements))
+		for i, el := range w.elements {
+			elements[i] = el.el
+		}
+		op.ready = append(op.ready, Window[T]{Start: w.start, End: w.end, Elements: elements})
+	}
+}
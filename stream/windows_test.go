@@ -0,0 +1,151 @@
+package stream_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+type reading struct {
+	At    int // seconds since the zero time
+	Value string
+}
+
+func readingTime(r reading) time.Time {
+	return time.Time{}.Add(time.Duration(r.At) * time.Second)
+}
+
+// windowValues returns the bounds of the windows in seconds, and the values of their elements.
+func windowValues(s stream.Stream[stream.Window[reading]]) [][]any {
+	return stream.Map(s, func(w stream.Window[reading]) []any {
+		values := []any{int(w.Start.Sub(time.Time{}).Seconds()), int(w.End.Sub(time.Time{}).Seconds())}
+		for _, r := range w.Elements {
+			values = append(values, r.Value)
+		}
+		return values
+	}).ToArray()
+}
+
+func TestTumblingWindows(t *testing.T) {
+	t.Run("in order", func(t *testing.T) {
+		s := stream.TumblingWindows(stream.Of(
+			reading{1, "a"}, reading{4, "b"}, reading{5, "c"}, reading{12, "d"}, reading{13, "e"},
+		), 5*time.Second, readingTime)
+
+		require.Equal(t, [][]any{
+			{0, 5, "a", "b"},
+			{5, 10, "c"},
+			{10, 15, "d", "e"},
+		}, windowValues(s))
+	})
+
+	t.Run("out of order and late elements", func(t *testing.T) {
+		var late []reading
+		s := stream.TumblingWindowsWithOptions(stream.Of(
+			reading{1, "a"}, reading{6, "b"}, reading{3, "c"}, reading{8, "d"}, reading{2, "e"}, reading{11, "f"},
+		), 5*time.Second, readingTime, stream.WindowOptions[reading]{
+			MaxOutOfOrderness: 3 * time.Second,
+			Late:              func(r reading) { late = append(late, r) },
+		})
+
+		require.Equal(t, [][]any{
+			{0, 5, "a", "c"},
+			{5, 10, "b", "d"},
+			{10, 15, "f"},
+		}, windowValues(s))
+		require.Equal(t, []reading{{2, "e"}}, late)
+	})
+
+	t.Run("allowed lateness", func(t *testing.T) {
+		var late []reading
+		s := stream.TumblingWindowsWithOptions(stream.Of(
+			reading{1, "a"}, reading{6, "b"}, reading{2, "c"}, reading{9, "d"}, reading{3, "e"},
+		), 5*time.Second, readingTime, stream.WindowOptions[reading]{
+			AllowedLateness: 2 * time.Second,
+			Late:            func(r reading) { late = append(late, r) },
+		})
+
+		require.Equal(t, [][]any{
+			{0, 5, "a"},
+			{0, 5, "a", "c"},
+			{5, 10, "b", "d"},
+		}, windowValues(s))
+		require.Equal(t, []reading{{3, "e"}}, late)
+	})
+
+	t.Run("lazy", func(t *testing.T) {
+		pulled := 0
+		s := stream.TumblingWindows(stream.Map(stream.Lines(strings.NewReader("1\n6\n11\n16\n")), func(line string) reading {
+			pulled++
+			at, _ := strconv.Atoi(line)
+			return reading{at, line}
+		}), 5*time.Second, readingTime)
+
+		require.Equal(t, []reading{{1, "1"}}, s.FindFirst().Elements)
+		require.Equal(t, 2, pulled)
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		require.Panics(t, func() { stream.TumblingWindows(stream.Of[reading](), 0, readingTime) })
+	})
+}
+
+func TestHoppingWindows(t *testing.T) {
+	s := stream.HoppingWindows(stream.Of(
+		reading{1, "a"}, reading{3, "b"}, reading{7, "c"},
+	), 4*time.Second, 2*time.Second, readingTime)
+
+	require.Equal(t, [][]any{
+		{-2, 2, "a"},
+		{0, 4, "a", "b"},
+		{2, 6, "b"},
+		{4, 8, "c"},
+		{6, 10, "c"},
+	}, windowValues(s))
+
+	t.Run("gaps between windows", func(t *testing.T) {
+		s := stream.HoppingWindows(stream.Of(
+			reading{1, "a"}, reading{3, "b"}, reading{5, "c"},
+		), 2*time.Second, 4*time.Second, readingTime)
+
+		require.Equal(t, [][]any{{0, 2, "a"}, {4, 6, "c"}}, windowValues(s))
+	})
+}
+
+func TestSessionWindows(t *testing.T) {
+	t.Run("sessions", func(t *testing.T) {
+		s := stream.SessionWindows(stream.Of(
+			reading{1, "a"}, reading{3, "b"}, reading{10, "c"}, reading{11, "d"}, reading{20, "e"},
+		), 3*time.Second, readingTime)
+
+		require.Equal(t, [][]any{
+			{1, 6, "a", "b"},
+			{10, 14, "c", "d"},
+			{20, 23, "e"},
+		}, windowValues(s))
+	})
+
+	t.Run("merging", func(t *testing.T) {
+		var late []reading
+		s := stream.SessionWindowsWithOptions(stream.Of(
+			reading{1, "a"}, reading{7, "b"}, reading{4, "c"}, reading{14, "d"}, reading{2, "e"}, reading{15, "f"}, reading{3, "g"},
+		), 3*time.Second, readingTime, stream.WindowOptions[reading]{
+			MaxOutOfOrderness: 5 * time.Second,
+			Late:              func(r reading) { late = append(late, r) },
+		})
+
+		require.Equal(t, [][]any{
+			{1, 10, "a", "b", "c", "e"},
+			{14, 18, "d", "f"},
+		}, windowValues(s))
+		require.Equal(t, []reading{{3, "g"}}, late)
+	})
+
+	t.Run("invalid gap", func(t *testing.T) {
+		require.Panics(t, func() { stream.SessionWindows(stream.Of[reading](), -time.Second, readingTime) })
+	})
+}
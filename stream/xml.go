@@ -0,0 +1,64 @@
+package stream
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// XMLElement is an element of the stream returned by DecodeXMLElements.
+type XMLElement[T any] struct {
+	// Value is the decoded element.
+	Value T
+	// Name is the name of the element, including its namespace.
+	Name xml.Name
+	// Offset is the byte offset of the start tag of the element in the input.
+	Offset int64
+}
+
+// DecodeXML returns a lazy stream of the XML elements with the given name read from r, each one decoded into T
+// using xml.Decoder.DecodeElement. Everything else in the document is skipped, without building it in memory.
+//
+// The name is either a local name (e.g. "product"), which matches elements in any namespace,
+// or a namespace URI and a local name separated by a space (e.g. "http://example.com/catalog product"),
+// like in the tags of encoding/xml.
+//
+// Decoding errors stop the stream and are reported by Err.
+//
+// NOTE: There is no such thing in the Java standard library.
+func DecodeXML[T any](r io.Reader, name string) Stream[T] {
+	return Map(DecodeXMLElements[T](r, name), func(e XMLElement[T]) T { return e.Value })
+}
+
+// DecodeXMLElements is like DecodeXML, but it also reports the name and the byte offset of each element.
+func DecodeXMLElements[T any](r io.Reader, name string) Stream[XMLElement[T]] {
+	dec := xml.NewDecoder(r)
+	want := xml.Name{Local: name}
+	if i := strings.LastIndex(name, " "); i >= 0 {
+		want = xml.Name{Space: name[:i], Local: name[i+1:]}
+	}
+
+	return newIteratorStream(func() (XMLElement[T], bool, error) {
+		var el XMLElement[T]
+		for {
+			offset := dec.InputOffset()
+			tok, err := dec.Token()
+			if err == io.EOF {
+				return el, false, nil
+			}
+			if err != nil {
+				return el, false, err
+			}
+			start, ok := tok.(xml.StartElement)
+			if !ok || start.Name.Local != want.Local || (want.Space != "" && start.Name.Space != want.Space) {
+				continue
+			}
+			if err := dec.DecodeElement(&el.Value, &start); err != nil {
+				return el, false, err
+			}
+			el.Name = start.Name
+			el.Offset = offset
+			return el, true, nil
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package stream_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+type catalogItem struct {
+	ID    string  `xml:"id,attr"`
+	Name  string  `xml:"name"`
+	Price float64 `xml:"price"`
+}
+
+const catalog = `<?xml version="1.0"?>
+<catalog xmlns="http://example.com/catalog" xmlns:old="http://example.com/legacy">
+	<meta><name>Winter</name></meta>
+	<product id="1"><name>Gloves</name><price>10.5</price></product>
+	<old:product id="2"><name>Scarf</name><price>7</price></old:product>
+	<section>
+		<product id="3"><name>Hat</name><price>12</price></product>
+	</section>
+</catalog>`
+
+func TestDecodeXML(t *testing.T) {
+	t.Run("local name", func(t *testing.T) {
+		s := stream.DecodeXML[catalogItem](strings.NewReader(catalog), "product")
+
+		require.Equal(t, []catalogItem{
+			{ID: "1", Name: "Gloves", Price: 10.5},
+			{ID: "2", Name: "Scarf", Price: 7},
+			{ID: "3", Name: "Hat", Price: 12},
+		}, s.ToArray())
+		require.NoError(t, s.Err())
+	})
+
+	t.Run("namespace", func(t *testing.T) {
+		s := stream.DecodeXML[catalogItem](strings.NewReader(catalog), "http://example.com/legacy product")
+
+		require.Equal(t, []catalogItem{{ID: "2", Name: "Scarf", Price: 7}}, s.ToArray())
+	})
+
+	t.Run("in a pipeline", func(t *testing.T) {
+		s := stream.DecodeXML[catalogItem](strings.NewReader(catalog), "product").
+			Filter(func(p catalogItem) bool { return p.Price > 10 })
+
+		require.Equal(t, int64(2), s.Count())
+	})
+
+	t.Run("offsets", func(t *testing.T) {
+		elements := stream.DecodeXMLElements[catalogItem](strings.NewReader(catalog), "http://example.com/catalog product").ToArray()
+
+		require.Len(t, elements, 2)
+		for _, e := range elements {
+			require.Equal(t, "http://example.com/catalog", e.Name.Space)
+			require.True(t, strings.HasPrefix(catalog[e.Offset:], `<product id="`+e.Value.ID+`"`))
+		}
+	})
+
+	t.Run("malformed document", func(t *testing.T) {
+		s := stream.DecodeXML[catalogItem](strings.NewReader(`<catalog><product id="1"><name>A</name></product><product>`), "product")
+
+		require.Equal(t, "1", s.FindFirst().ID)
+		require.NoError(t, s.Err())
+
+		s = stream.DecodeXML[catalogItem](strings.NewReader(`<catalog><product id="1"><name>A</name></product><product>`), "product")
+		require.Nil(t, s.ToArray())
+		require.Error(t, s.Err())
+	})
+}
@@ -0,0 +1,108 @@
+package stream
+
+// Zip returns a lazy stream of the pairs of the elements of a and b at the same positions.
+// The stream ends when either a or b ends.
+// Closing the stream closes both a and b, and the errors of both are reported by Err.
+// The stream is in Recover mode if either a or b is.
+//
+// NOTE: There is no such thing in the Java standard library.
+func Zip[A any, B any](a Stream[A], b Stream[B]) Stream[Pair[A, B]] {
+	return zipped(a, b, func(la *IteratorStream[A], lb *IteratorStream[B]) (Pair[A, B], bool) {
+		first, ok := la.pull()
+		if !ok {
+			return Pair[A, B]{}, false
+		}
+		second, ok := lb.pull()
+		if !ok {
+			return Pair[A, B]{}, false
+		}
+		return Pair[A, B]{First: first, Second: second}, true
+	})
+}
+
+// ZipWith returns a lazy stream of the results of applying the given function to the elements of a and b at the same positions.
+// It ends and is closed as Zip.
+//
+// NOTE: There is no such thing in the Java standard library.
+func ZipWith[A any, B any, R any](a Stream[A], b Stream[B], zipper func(A, B) R) Stream[R] {
+	return Map(Zip(a, b), func(p Pair[A, B]) R { return zipper(p.First, p.Second) })
+}
+
+// ZipLongest returns a lazy stream of the pairs of the elements of a and b at the same positions,
+// until both a and b end.
+// The side of the stream that ended first is nil in the remaining pairs.
+// It is closed as Zip.
+//
+// NOTE: There is no such thing in the Java standard library.
+func ZipLongest[A any, B any](a Stream[A], b Stream[B]) Stream[Pair[*A, *B]] {
+	return zipped(a, b, func(la *IteratorStream[A], lb *IteratorStream[B]) (Pair[*A, *B], bool) {
+		var p Pair[*A, *B]
+		if first, ok := la.pull(); ok {
+			p.First = &first
+		}
+		if second, ok := lb.pull(); ok {
+			p.Second = &second
+		}
+		return p, p.First != nil || p.Second != nil
+	})
+}
+
+// ZipLongestWithFill is like ZipLongest, but the side of the stream that ended first is
+// fillA or fillB in the remaining pairs.
+//
+// NOTE: There is no such thing in the Java standard library.
+func ZipLongestWithFill[A any, B any](a Stream[A], b Stream[B], fillA A, fillB B) Stream[Pair[A, B]] {
+	return Map(ZipLongest(a, b), func(p Pair[*A, *B]) Pair[A, B] {
+		filled := Pair[A, B]{First: fillA, Second: fillB}
+		if p.First != nil {
+			filled.First = *p.First
+		}
+		if p.Second != nil {
+			filled.Second = *p.Second
+		}
+		return filled
+	})
+}
+
+// ZipWithIndex returns a lazy stream of the elements of the stream with their indexes, starting from 0.
+//
+// NOTE: There is no such thing in the Java standard library.
+func ZipWithIndex[T any](stream Stream[T]) Stream[Indexed[T]] {
+	s := lazy(stream)
+	var i int64
+	return deriveIteratorStream(s, func() (Indexed[T], bool) {
+		el, ok := s.pull()
+		if !ok {
+			return Indexed[T]{}, false
+		}
+		i++
+		return Indexed[T]{Index: i - 1, Value: el}, true
+	})
+}
+
+// zipped returns a lazy stream of the elements produced by next from the elements of a and b.
+// The stream stops on the first error of a or b, and closing it closes both.
+// It is in Recover mode if either a or b is, and then a panic inside next is reported by Err.
+func zipped[A any, B any, R any](a Stream[A], b Stream[B], next func(*IteratorStream[A], *IteratorStream[B]) (R, bool)) Stream[R] {
+	la, lb := lazy(a), lazy(b)
+	state := &pipelineState{
+		closeHandlers: []func(){la.Close, lb.Close},
+		recover:       la.state.recover || lb.state.recover,
+	}
+	var index int64
+	return newIteratorStreamOn(state, func() (R, bool, error) {
+		var r R
+		var ok bool
+		if err := catch(state.recover, index, func() { r, ok = next(la, lb) }); err != nil {
+			return r, false, err
+		}
+		index++
+		if la.state.err != nil {
+			return r, false, la.state.err
+		}
+		if lb.state.err != nil {
+			return r, false, lb.state.err
+		}
+		return r, ok, nil
+	})
+}
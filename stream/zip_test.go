@@ -0,0 +1,85 @@
+package stream_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/asankov/go-streams/stream"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZip(t *testing.T) {
+	t.Run("shortest", func(t *testing.T) {
+		s := stream.Zip(stream.Of("a", "b", "c"), stream.Of(1, 2))
+
+		require.Equal(t, []stream.Pair[string, int]{{"a", 1}, {"b", 2}}, s.ToArray())
+	})
+
+	t.Run("lazy", func(t *testing.T) {
+		names := stream.Lines(strings.NewReader("ada\nalan\n"))
+		s := stream.Zip(names, stream.Of(1815, 1912, 1906))
+
+		require.Equal(t, stream.Pair[string, int]{"ada", 1815}, *s.FindFirst())
+	})
+
+	t.Run("close closes both", func(t *testing.T) {
+		var closed []string
+		a := stream.Of(1).OnClose(func() { closed = append(closed, "a") })
+		b := stream.Lines(strings.NewReader("x")).OnClose(func() { closed = append(closed, "b") })
+
+		s := stream.Zip(a, b)
+		s.Close()
+		s.Close()
+
+		require.Equal(t, []string{"a", "b"}, closed)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		failing := stream.Lines(io.MultiReader(strings.NewReader("x\n"), iotest.ErrReader(errors.New("read failed"))))
+		s := stream.Zip(stream.Of(1, 2, 3), failing)
+
+		require.Nil(t, s.ToArray())
+		require.EqualError(t, s.Err(), "read failed")
+	})
+}
+
+func TestZipWith(t *testing.T) {
+	s := stream.ZipWith(stream.Of(1, 2, 3), stream.Of(10, 20, 30), func(a, b int) int { return a * b })
+
+	require.Equal(t, []int{10, 40, 90}, s.ToArray())
+
+	t.Run("recover", func(t *testing.T) {
+		s := stream.ZipWith(stream.Of(1, 2, 3).Recover(), stream.Of(10, 20, 30), func(a, b int) int {
+			boomOn(a, 2)
+			return a * b
+		})
+
+		require.Nil(t, s.ToArray())
+		requireBoom(t, s.Err())
+	})
+}
+
+func TestZipLongest(t *testing.T) {
+	pairs := stream.ZipLongest(stream.Of("a", "b", "c"), stream.Of(1)).ToArray()
+
+	require.Len(t, pairs, 3)
+	require.Equal(t, "a", *pairs[0].First)
+	require.Equal(t, 1, *pairs[0].Second)
+	require.Equal(t, "c", *pairs[2].First)
+	require.Nil(t, pairs[2].Second)
+
+	t.Run("fill", func(t *testing.T) {
+		s := stream.ZipLongestWithFill(stream.Of("a"), stream.Of(1, 2), "-", 0)
+
+		require.Equal(t, []stream.Pair[string, int]{{"a", 1}, {"-", 2}}, s.ToArray())
+	})
+}
+
+func TestZipWithIndex(t *testing.T) {
+	s := stream.ZipWithIndex(stream.Of("a", "b"))
+
+	require.Equal(t, []stream.Indexed[string]{{0, "a"}, {1, "b"}}, s.ToArray())
+}